@@ -1,10 +1,21 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ddod/leanmcp-cli/internal/api"
+	"github.com/ddod/leanmcp-cli/internal/auth"
+	"github.com/ddod/leanmcp-cli/internal/config"
+	"github.com/ddod/leanmcp-cli/internal/display"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var apiKeysCmd = &cobra.Command{
@@ -16,38 +27,229 @@ var apiKeysCmd = &cobra.Command{
 var apiKeysListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List API keys",
-	Long:  "List all API keys associated with your account",
+	Long:  "List all API keys associated with your account. The key in use is marked with *.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := getAuthenticatedClient()
+		if err != nil {
+			return handleAuthError(err)
+		}
+
+		statusf("🔑 Fetching API keys...\n")
+
+		keys, err := client.ListAPIKeys()
+		if err != nil {
+			return fmt.Errorf("failed to list API keys: %v", err)
+		}
+
+		// Only used to mark the current key
+		var currentID string
+		if current, err := client.GetAPIKeyInfo(); err == nil {
+			currentID = current.ID
+		}
+
+		return printer.Print(keys, func() {
+			display.APIKeysTable(keys, currentID)
+		})
+	},
+}
+
+var apiKeysCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key",
+	Long: `Create a new API key for your account. The key is shown only once, so
+copy it to where it will be used.
+
+--expires takes a number of days (90d), a duration (720h), a date
+(2025-12-31) or "never".
+
+Example:
+  leanmcp-cli api-keys create --name ci --scopes BUILD_AND_DEPLOY --expires 90d`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		scopes, _ := cmd.Flags().GetStringSlice("scopes")
+		expires, _ := cmd.Flags().GetString("expires")
+
+		expiresAt, err := parseExpires(expires)
 		if err != nil {
 			return err
 		}
 
-		fmt.Println("🔑 Fetching API key information...")
+		client, err := getAuthenticatedClient()
+		if err != nil {
+			return handleAuthError(err)
+		}
+
+		statusf("🔑 Creating API key %s...\n", name)
 
-		// Try to get info about the current API key
-		keyInfo, err := client.GetAPIKeyInfo()
+		key, err := client.CreateAPIKey(api.CreateAPIKeyRequest{
+			Name:      name,
+			Scopes:    normalizeScopes(scopes),
+			ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create API key: %v", err)
+		}
+
+		return printer.Print(key, func() {
+			fmt.Printf("✅ %s\n\n", color.GreenString("API key created!"))
+			printAPIKey(&key.APIKeyInfo)
+			fmt.Printf("\n%s %s\n", color.CyanString("Key:"), color.GreenString(key.Key))
+			fmt.Printf("%s\n", color.YellowString("Copy the key now, it will not be shown again."))
+		})
+	},
+}
+
+var apiKeysRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke an API key",
+	Long:  "Revoke an API key by its ID. Requests made with the key are rejected afterwards. This action cannot be undone.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := getAuthenticatedClient()
+		if err != nil {
+			return handleAuthError(err)
+		}
+
+		keyID := args[0]
+
+		// Warn before cutting off the key this CLI uses
+		var inUse bool
+		if current, err := client.GetAPIKeyInfo(); err == nil {
+			inUse = current.ID == keyID
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		if !force {
+			statusf("⚠️  %s\n", color.YellowString("WARNING: This will permanently revoke the API key."))
+			if inUse {
+				statusf("⚠️  %s\n", color.YellowString("This is the API key you are logged in with."))
+			}
+			statusf("Use --force to confirm revocation.\n")
+			return nil
+		}
+
+		statusf("🗑️  Revoking API key %s...\n", keyID)
+
+		if err := client.RevokeAPIKey(keyID); err != nil {
+			return fmt.Errorf("failed to revoke API key: %v", err)
+		}
+
+		statusf("✅ %s\n", color.GreenString("API key revoked successfully!"))
+		if inUse {
+			statusf("Log in with another key: %s\n", color.CyanString("leanmcp-cli auth login --api-key <your-key>"))
+		}
+
+		return nil
+	},
+}
+
+var apiKeysRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Replace the current API key",
+	Long: `Create a replacement for the API key you are logged in with, store it
+in place of the current one and revoke the current key.
+
+The new key gets the same name and scopes. A key that expires gets the same
+lifetime again, unless --expires is given. If the server does not report when
+the key was created, --expires is required. The old key is revoked after you
+confirm, or right away with --force.
+
+Example:
+  leanmcp-cli api-keys rotate
+  leanmcp-cli api-keys rotate --expires 90d --force`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		creds, err := auth.LoadCredentials()
+		if errors.Is(err, auth.ErrNoCredentials) {
+			return handleAuthError(errNotAuthenticated)
+		}
+		if err != nil {
+			return err
+		}
+		if creds.External() {
+			return fmt.Errorf("the API key comes from %s and cannot be replaced; create a key with 'leanmcp-cli api-keys create' and update it there", auth.StoreDescription(creds.Store))
+		}
+
+		baseURL, err := config.GetBaseURL()
+		if err != nil {
+			return err
+		}
+		client := api.NewClient(creds.APIKey, baseURL)
+
+		oldKey, err := client.GetAPIKeyInfo()
 		if err != nil {
 			return fmt.Errorf("failed to get API key info: %v", err)
 		}
 
-		fmt.Printf("✅ %s\n\n", color.GreenString("Current API Key Information:"))
-		fmt.Printf("%s %s\n", color.CyanString("ID:"), keyInfo.ID)
-		fmt.Printf("%s %s\n", color.CyanString("Name:"), keyInfo.Name)
-		fmt.Printf("%s %v\n", color.CyanString("Scopes:"), keyInfo.Scopes)
-		fmt.Printf("%s %t\n", color.CyanString("Active:"), keyInfo.IsActive)
-		fmt.Printf("%s %s\n", color.CyanString("Created:"), keyInfo.CreatedAt.Format("2006-01-02 15:04:05"))
-		
-		if keyInfo.ExpiresAt != nil {
-			fmt.Printf("%s %s\n", color.CyanString("Expires:"), keyInfo.ExpiresAt.Format("2006-01-02 15:04:05"))
+		req := api.CreateAPIKeyRequest{Name: oldKey.Name, Scopes: oldKey.Scopes}
+		if name, _ := cmd.Flags().GetString("name"); name != "" {
+			req.Name = name
+		}
+		if cmd.Flags().Changed("expires") {
+			expires, _ := cmd.Flags().GetString("expires")
+			if req.ExpiresAt, err = parseExpires(expires); err != nil {
+				return err
+			}
+		} else if oldKey.ExpiresAt != nil {
+			// Without a creation time the lifetime is unknown, and a key
+			// that expires must not be replaced by one that never does
+			lifetime := oldKey.ExpiresAt.Sub(oldKey.CreatedAt)
+			if oldKey.CreatedAt.IsZero() || lifetime <= 0 {
+				return fmt.Errorf("cannot tell the lifetime of API key %s; pass --expires to choose one", oldKey.ID)
+			}
+			expiresAt := time.Now().Add(lifetime).Truncate(time.Second)
+			req.ExpiresAt = &expiresAt
+		}
+
+		statusf("🔄 Creating a replacement for API key %s (%s)...\n", oldKey.Name, oldKey.ID)
+
+		newKey, err := client.CreateAPIKey(req)
+		if err != nil {
+			return fmt.Errorf("failed to create API key: %v", err)
+		}
+
+		stored, err := auth.StoreCredentials(newKey.Key, &auth.UserInfo{
+			Email:     creds.UserEmail,
+			Scopes:    newKey.Scopes,
+			KeyName:   newKey.Name,
+			ExpiresAt: newKey.ExpiresAt,
+		})
+		if err != nil {
+			// The new key exists but is only known here, so don't lose it
+			statusf("%s %s\n", color.YellowString("New API key:"), newKey.Key)
+			return fmt.Errorf("failed to store the new API key, the old key was not revoked; store it with 'leanmcp-cli auth login --with-token': %v", err)
+		}
+		statusf("✅ New API key %s (%s) stored in %s\n", newKey.Name, newKey.ID, auth.StoreDescription(stored.Store))
+
+		output := &rotateOutput{OldKeyID: oldKey.ID, NewKey: newKey.APIKeyInfo}
+
+		force, _ := cmd.Flags().GetBool("force")
+		if !force && !confirm(fmt.Sprintf("Revoke the old API key %s (%s)?", oldKey.Name, oldKey.ID)) {
+			statusf("The old API key is still active. Revoke it later with: %s\n",
+				color.CyanString("leanmcp-cli api-keys revoke %s --force", oldKey.ID))
 		} else {
-			fmt.Printf("%s %s\n", color.CyanString("Expires:"), "Never")
+			newClient := api.NewClient(newKey.Key, baseURL)
+			if err := newClient.RevokeAPIKey(oldKey.ID); err != nil {
+				return fmt.Errorf("new API key stored, but revoking the old key failed: %v", err)
+			}
+			output.Revoked = true
+			statusf("🗑️  Old API key %s revoked\n", oldKey.ID)
 		}
 
-		return nil
+		return printer.Print(output, func() {
+			fmt.Printf("✅ %s\n\n", color.GreenString("API key rotated!"))
+			printAPIKey(&output.NewKey)
+		})
 	},
 }
 
+// rotateOutput is the machine-readable form of 'api-keys rotate'. The new
+// secret is stored, not printed.
+type rotateOutput struct {
+	OldKeyID string         `json:"oldKeyId"`
+	Revoked  bool           `json:"revoked"`
+	NewKey   api.APIKeyInfo `json:"newKey"`
+}
+
 var apiKeysInfoCmd = &cobra.Command{
 	Use:   "info",
 	Short: "Show current API key info",
@@ -58,54 +260,145 @@ var apiKeysInfoCmd = &cobra.Command{
 			return err
 		}
 
-		fmt.Println("🔍 Getting API key information...")
+		statusf("🔍 Getting API key information...\n")
 
 		keyInfo, err := client.GetAPIKeyInfo()
 		if err != nil {
 			return fmt.Errorf("failed to get API key info: %v", err)
 		}
 
-		fmt.Printf("✅ %s\n\n", color.GreenString("API Key Details:"))
-		fmt.Printf("%s %s\n", color.CyanString("ID:"), keyInfo.ID)
-		fmt.Printf("%s %s\n", color.CyanString("Name:"), keyInfo.Name)
-		
-		// Display scopes with colors
-		fmt.Printf("%s ", color.CyanString("Scopes:"))
-		for i, scope := range keyInfo.Scopes {
-			if i > 0 {
-				fmt.Print(", ")
+		return printer.Print(keyInfo, func() {
+			fmt.Printf("✅ %s\n\n", color.GreenString("API Key Details:"))
+			fmt.Printf("%s %s\n", color.CyanString("ID:"), keyInfo.ID)
+			fmt.Printf("%s %s\n", color.CyanString("Name:"), keyInfo.Name)
+
+			// Display scopes with colors
+			fmt.Printf("%s ", color.CyanString("Scopes:"))
+			for i, scope := range keyInfo.Scopes {
+				if i > 0 {
+					fmt.Print(", ")
+				}
+				scopeColor := color.GreenString
+				switch scope {
+				case "ADMIN":
+					scopeColor = color.RedString
+				case "BUILD_AND_DEPLOY":
+					scopeColor = color.YellowString
+				}
+				fmt.Print(scopeColor(scope))
 			}
-			scopeColor := color.GreenString
-			switch scope {
-			case "ADMIN":
-				scopeColor = color.RedString
-			case "BUILD_AND_DEPLOY":
-				scopeColor = color.YellowString
+			fmt.Println()
+
+			status := "❌ Inactive"
+			if keyInfo.IsActive {
+				status = "✅ Active"
 			}
-			fmt.Print(scopeColor(scope))
-		}
-		fmt.Println()
-		
-		status := "❌ Inactive"
-		if keyInfo.IsActive {
-			status = "✅ Active"
-		}
-		fmt.Printf("%s %s\n", color.CyanString("Status:"), status)
-		
-		fmt.Printf("%s %s\n", color.CyanString("Created:"), keyInfo.CreatedAt.Format("2006-01-02 15:04:05"))
-		
-		if keyInfo.ExpiresAt != nil {
-			fmt.Printf("%s %s\n", color.CyanString("Expires:"), keyInfo.ExpiresAt.Format("2006-01-02 15:04:05"))
-		} else {
-			fmt.Printf("%s %s\n", color.CyanString("Expires:"), color.GreenString("Never"))
-		}
+			fmt.Printf("%s %s\n", color.CyanString("Status:"), status)
 
-		return nil
+			fmt.Printf("%s %s\n", color.CyanString("Created:"), keyInfo.CreatedAt.Format("2006-01-02 15:04:05"))
+
+			if keyInfo.ExpiresAt != nil {
+				fmt.Printf("%s %s\n", color.CyanString("Expires:"), keyInfo.ExpiresAt.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Printf("%s %s\n", color.CyanString("Expires:"), color.GreenString("Never"))
+			}
+		})
 	},
 }
 
+// printAPIKey shows the details of an API key, without its secret
+func printAPIKey(key *api.APIKeyInfo) {
+	fmt.Printf("%s %s\n", color.CyanString("ID:"), key.ID)
+	fmt.Printf("%s %s\n", color.CyanString("Name:"), key.Name)
+	fmt.Printf("%s %v\n", color.CyanString("Scopes:"), key.Scopes)
+	if key.ExpiresAt != nil {
+		fmt.Printf("%s %s\n", color.CyanString("Expires:"), key.ExpiresAt.Format("2006-01-02 15:04:05"))
+	} else {
+		fmt.Printf("%s %s\n", color.CyanString("Expires:"), "Never")
+	}
+}
+
+// parseExpires parses the --expires flag: a number of days such as "90d", a
+// duration such as "720h", a date, an RFC 3339 time, or "never". An empty
+// value or "never" returns nil.
+func parseExpires(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "never") {
+		return nil, nil
+	}
+
+	now := time.Now()
+	var expiresAt time.Time
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --expires %q: %v", value, err)
+		}
+		expiresAt = now.AddDate(0, 0, n)
+	} else if d, err := time.ParseDuration(value); err == nil {
+		expiresAt = now.Add(d)
+	} else if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		expiresAt = t
+	} else if t, err := time.Parse(time.RFC3339, value); err == nil {
+		expiresAt = t
+	} else {
+		return nil, fmt.Errorf("invalid --expires %q: use days (90d), a duration (720h), a date (2006-01-02) or never", value)
+	}
+
+	expiresAt = expiresAt.Truncate(time.Second)
+	if !expiresAt.After(now) {
+		return nil, fmt.Errorf("invalid --expires %q: must be in the future", value)
+	}
+	return &expiresAt, nil
+}
+
+// normalizeScopes upper-cases scopes, which the API names like BUILD_AND_DEPLOY
+func normalizeScopes(scopes []string) []string {
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if scope = strings.ToUpper(strings.TrimSpace(scope)); scope != "" {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized
+}
+
+// confirm asks a yes/no question on the terminal. It returns false when stdin
+// is not a terminal.
+func confirm(question string) bool {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(apiKeysCmd)
 	apiKeysCmd.AddCommand(apiKeysListCmd)
 	apiKeysCmd.AddCommand(apiKeysInfoCmd)
+	apiKeysCmd.AddCommand(apiKeysCreateCmd)
+	apiKeysCmd.AddCommand(apiKeysRevokeCmd)
+	apiKeysCmd.AddCommand(apiKeysRotateCmd)
+
+	// Create command flags
+	apiKeysCreateCmd.Flags().String("name", "", "Name of the API key (required)")
+	apiKeysCreateCmd.Flags().StringSlice("scopes", nil, "Comma-separated scopes, e.g. BUILD_AND_DEPLOY,CHAT (required)")
+	apiKeysCreateCmd.Flags().String("expires", "", "When the key expires: 90d, 720h, 2025-12-31 or never (default never)")
+	apiKeysCreateCmd.MarkFlagRequired("name")
+	apiKeysCreateCmd.MarkFlagRequired("scopes")
+
+	// Revoke command flags
+	apiKeysRevokeCmd.Flags().Bool("force", false, "Revoke without confirmation")
+
+	// Rotate command flags
+	apiKeysRotateCmd.Flags().String("name", "", "Name of the new key (default the current key's name)")
+	apiKeysRotateCmd.Flags().String("expires", "", "When the new key expires (default the current key's lifetime)")
+	apiKeysRotateCmd.Flags().Bool("force", false, "Revoke the old key without confirmation")
 }
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseExpires(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	future := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		value   string
+		want    time.Duration // from now, 0 for never
+		date    string        // expected local date instead of want
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "never", want: 0},
+		{value: " Never ", want: 0},
+		{value: "90d", want: 90 * 24 * time.Hour},
+		{value: "1d", want: 24 * time.Hour},
+		{value: "720h", want: 720 * time.Hour},
+		{value: "90m", want: 90 * time.Minute},
+		{value: tomorrow, date: tomorrow},
+		{value: future, want: 48 * time.Hour},
+		{value: "0d", wantErr: true},
+		{value: "-5d", wantErr: true},
+		{value: "-1h", wantErr: true},
+		{value: "2001-01-01", wantErr: true},
+		{value: past, wantErr: true},
+		{value: "xd", wantErr: true},
+		{value: "soon", wantErr: true},
+		{value: "2025-13-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseExpires(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExpires(%q) error = %v, want error: %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			switch {
+			case tt.date != "":
+				if got == nil || got.In(time.Local).Format("2006-01-02") != tt.date {
+					t.Errorf("parseExpires(%q) = %v, want %s", tt.value, got, tt.date)
+				}
+			case tt.want == 0:
+				if got != nil {
+					t.Errorf("parseExpires(%q) = %v, want never", tt.value, got)
+				}
+			default:
+				if got == nil {
+					t.Fatalf("parseExpires(%q) = never, want in %v", tt.value, tt.want)
+				}
+				// Allow for the time the test takes and the truncation to seconds
+				if diff := time.Until(*got) - tt.want; diff < -5*time.Second || diff > time.Second {
+					t.Errorf("parseExpires(%q) = %v, want about %v from now", tt.value, got, tt.want)
+				}
+				if got.Nanosecond() != 0 {
+					t.Errorf("parseExpires(%q) = %v, want whole seconds", tt.value, got)
+				}
+			}
+		})
+	}
+}
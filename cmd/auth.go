@@ -1,12 +1,21 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 	"github.com/ddod/leanmcp-cli/internal/auth"
 	"github.com/ddod/leanmcp-cli/internal/api"
+	"github.com/ddod/leanmcp-cli/internal/config"
+	"github.com/ddod/leanmcp-cli/internal/display"
 )
 
 var authCmd = &cobra.Command{
@@ -20,15 +29,44 @@ var loginCmd = &cobra.Command{
 	Short: "Authenticate with API key",
 	Long: `Authenticate with your LeanMCP API key.
 
-Your API key should start with 'airtrain_' and will be stored securely
-in your local configuration file (~/.leanmcp-cli/config.yaml).
+Your API key should start with 'airtrain_'. It is stored in the OS keyring
+(the Secret Service on Linux, the Keychain on macOS, the Credential Manager
+on Windows). Where no keyring is available it is stored in
+~/.leanmcp-cli/credentials.json, encrypted with a passphrase you choose; set
+LEANMCP_PASSPHRASE to provide it without a prompt.
+
+The key is stored in the active profile. Use --profile to log in to another
+account; --api-url or --env given with it are remembered by that profile.
 
 Example:
-  leanmcp-cli auth login --api-key airtrain_your_key_here`,
+  leanmcp-cli auth login --api-key airtrain_your_key_here
+
+  # Keep a second account in the "work" profile
+  leanmcp-cli auth login --profile work --api-key airtrain_work_key --env staging
+
+  # Use the encrypted file even if a keyring is available
+  leanmcp-cli auth login --api-key airtrain_your_key_here --credential-store file
+
+  # Read the key from stdin, keeping it out of shell history
+  leanmcp-cli auth login --with-token < key.txt
+
+The key is checked with the API first: inactive and expired keys are
+rejected, and the key's name, scopes and expiry are saved for 'auth whoami'.
+
+In CI, no login is needed: set LEANMCP_API_KEY, or set credential_helper in
+the config file to a program that prints the key. Either takes precedence
+over a stored key.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		apiKey, _ := cmd.Flags().GetString("api-key")
+		if withToken, _ := cmd.Flags().GetBool("with-token"); withToken {
+			token, err := readTokenFromStdin()
+			if err != nil {
+				return err
+			}
+			apiKey = token
+		}
 		if apiKey == "" {
-			return fmt.Errorf("--api-key is required")
+			return fmt.Errorf("--api-key or --with-token is required")
 		}
 
 		// Validate API key format
@@ -36,17 +74,81 @@ Example:
 			return fmt.Errorf("invalid API key format: %v", err)
 		}
 
-		fmt.Println("🔐 Authenticating with API key...")
+		storeName, _ := cmd.Flags().GetString("credential-store")
+		if storeName != "" {
+			if _, err := auth.OpenCredentialStore(storeName); err != nil {
+				return err
+			}
+		}
+
+		baseURL, err := config.GetBaseURL()
+		if err != nil {
+			return err
+		}
+
+		statusf("🔐 Authenticating with API key...\n")
+
+		// Check the key with the API before changing any settings, so a bad
+		// key leaves a working login alone
+		var userInfo *auth.UserInfo
+		if skip, _ := cmd.Flags().GetBool("skip-validation"); !skip {
+			keyInfo, err := api.NewClient(apiKey, baseURL).GetAPIKeyInfo()
+			if err != nil {
+				return fmt.Errorf("failed to validate API key against %s: %v", baseURL, err)
+			}
+			if err := checkAPIKeyUsable(keyInfo); err != nil {
+				return err
+			}
+			userInfo = &auth.UserInfo{
+				Scopes:    keyInfo.Scopes,
+				KeyName:   keyInfo.Name,
+				ExpiresAt: keyInfo.ExpiresAt,
+			}
+		}
 
-		// For now, just store the credentials without validation
-		// (as requested by user - no actual API validation)
-		if err := auth.StoreCredentials(apiKey, nil); err != nil {
+		previousStore := config.GetProfileString("credential_store")
+		if storeName != "" {
+			config.SetProfileString("credential_store", storeName)
+		}
+
+		// Remember the API given on the command line for this profile
+		if cmd.Flags().Changed("api-url") || cmd.Flags().Changed("env") {
+			config.SetProfileString("base_url", baseURL)
+		}
+
+		creds, err := auth.StoreCredentials(apiKey, userInfo)
+		if err != nil {
 			return fmt.Errorf("failed to store credentials: %v", err)
 		}
 
-		fmt.Printf("✅ %s\n", color.GreenString("Successfully stored API key!"))
-		fmt.Printf("Your API key has been securely stored in ~/.leanmcp-cli/config.yaml\n")
-		fmt.Printf("You can now use other commands to interact with the API.\n")
+		// Only remove the old key once the new one is stored elsewhere
+		if previousStore != "" && previousStore != creds.Store {
+			if err := auth.RemoveStoredKey(previousStore); err != nil {
+				statusf("%s could not remove the previous API key from %s: %v\n",
+					color.YellowString("Warning:"), auth.StoreDescription(previousStore), err)
+			}
+		}
+
+		statusf("✅ %s\n", color.GreenString("Successfully stored API key!"))
+		statusf("Your API key has been stored in %s.\n", auth.StoreDescription(creds.Store))
+		statusf("%s %s\n", color.CyanString("Profile:"), config.ActiveProfile())
+		if creds.KeyName != "" {
+			statusf("%s %s\n", color.CyanString("Key:"), creds.KeyName)
+		}
+		if len(creds.Scopes) > 0 {
+			statusf("%s %v\n", color.CyanString("Scopes:"), creds.Scopes)
+		}
+		if userInfo != nil {
+			statusf("%s %s\n", color.CyanString("Expires:"), describeExpiry(creds.ExpiresAt))
+		}
+		statusf("You can now use other commands to interact with the API.\n")
+
+		if os.Getenv(auth.APIKeyEnv) != "" {
+			statusf("%s %s is set and takes precedence over the stored key.\n", color.YellowString("Note:"), auth.APIKeyEnv)
+		}
+		if profile := config.ActiveProfile(); profile != config.CurrentProfile() {
+			statusf("Run 'leanmcp-cli auth switch %s' to make it the current profile, or pass --profile %s.\n", profile, profile)
+		}
 		
 		return nil
 	},
@@ -55,14 +157,24 @@ Example:
 var logoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Remove stored credentials",
-	Long:  "Remove stored API key and authentication information from local configuration.",
+	Long: `Remove the stored API key and authentication information of the active
+profile. Logging out of a profile other than the default one removes the
+profile and its settings.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := auth.ClearCredentials(); err != nil {
 			return fmt.Errorf("failed to clear credentials: %v", err)
 		}
 
-		fmt.Printf("✅ %s\n", color.GreenString("Successfully logged out!"))
-		fmt.Printf("Your stored credentials have been removed.\n")
+		if profile := config.ActiveProfile(); profile != config.DefaultProfile {
+			config.DeleteProfile(profile)
+			if err := config.SaveConfig(); err != nil {
+				return fmt.Errorf("failed to remove profile %s: %v", profile, err)
+			}
+			statusf("Profile %s has been removed.\n", profile)
+		}
+
+		statusf("✅ %s\n", color.GreenString("Successfully logged out!"))
+		statusf("Your stored credentials have been removed.\n")
 		
 		return nil
 	},
@@ -73,60 +185,284 @@ var whoamiCmd = &cobra.Command{
 	Short: "Show current authentication status",
 	Long:  "Display information about the currently stored API key and authentication status.",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		migrateLegacyAPIKey()
+
 		creds, err := auth.LoadCredentials()
+		if err != nil && !errors.Is(err, auth.ErrNoCredentials) {
+			return err
+		}
 		if err != nil {
-			fmt.Printf("❌ %s\n", color.RedString("Not authenticated"))
-			fmt.Printf("Run 'leanmcp-cli auth login --api-key <your-key>' to authenticate.\n")
-			return nil
+			return printer.Print(&whoamiOutput{Profile: config.ActiveProfile(), Authenticated: false}, func() {
+				fmt.Printf("❌ %s (profile %s)\n", color.RedString("Not authenticated"), config.ActiveProfile())
+				fmt.Printf("Run 'leanmcp-cli auth login --api-key <your-key>' to authenticate.\n")
+			})
 		}
 
-		fmt.Printf("✅ %s\n", color.GreenString("Authenticated"))
-		fmt.Printf("%s %s\n", color.CyanString("API Key:"), maskAPIKey(creds.APIKey))
-		
-		if creds.UserEmail != "" {
-			fmt.Printf("%s %s\n", color.CyanString("Email:"), creds.UserEmail)
+		output := &whoamiOutput{
+			Profile:       config.ActiveProfile(),
+			Authenticated: true,
+			APIKey:        maskAPIKey(creds.APIKey),
+			UserEmail:     creds.UserEmail,
+			Scopes:        creds.Scopes,
+			StoredAt:      creds.StoredAt,
+			Store:         creds.Store,
+			KeyName:       creds.KeyName,
+			ExpiresAt:     creds.ExpiresAt,
 		}
-		
-		if len(creds.Scopes) > 0 {
-			fmt.Printf("%s %v\n", color.CyanString("Scopes:"), creds.Scopes)
+		if creds.ExpiresAt != nil {
+			days := daysUntil(*creds.ExpiresAt)
+			output.DaysUntilExpiry = &days
 		}
-		
-		fmt.Printf("%s %s\n", color.CyanString("Stored:"), creds.StoredAt.Format("2006-01-02 15:04:05"))
-		
-		return nil
+
+		return printer.Print(output, func() {
+			fmt.Printf("✅ %s\n", color.GreenString("Authenticated"))
+			fmt.Printf("%s %s\n", color.CyanString("Profile:"), output.Profile)
+			fmt.Printf("%s %s\n", color.CyanString("API Key:"), maskAPIKey(creds.APIKey))
+
+			if creds.KeyName != "" {
+				fmt.Printf("%s %s\n", color.CyanString("Key Name:"), creds.KeyName)
+			}
+
+			if creds.UserEmail != "" {
+				fmt.Printf("%s %s\n", color.CyanString("Email:"), creds.UserEmail)
+			}
+
+			if len(creds.Scopes) > 0 {
+				fmt.Printf("%s %v\n", color.CyanString("Scopes:"), creds.Scopes)
+			}
+
+			// Keys stored without validation have no known expiry
+			if creds.KeyName != "" || creds.ExpiresAt != nil {
+				fmt.Printf("%s %s\n", color.CyanString("Expires:"), describeExpiry(creds.ExpiresAt))
+			}
+
+			if creds.External() {
+				fmt.Printf("%s %s\n", color.CyanString("Source:"), auth.StoreDescription(creds.Store))
+				return
+			}
+			fmt.Printf("%s %s\n", color.CyanString("Stored:"), creds.StoredAt.Format("2006-01-02 15:04:05"))
+			fmt.Printf("%s %s\n", color.CyanString("Stored in:"), auth.StoreDescription(creds.Store))
+		})
 	},
 }
 
+// whoamiOutput is the machine-readable form of 'auth whoami'
+type whoamiOutput struct {
+	Profile         string     `json:"profile"`
+	Authenticated   bool       `json:"authenticated"`
+	APIKey          string     `json:"apiKey,omitempty"`
+	KeyName         string     `json:"keyName,omitempty"`
+	UserEmail       string     `json:"userEmail,omitempty"`
+	Scopes          []string   `json:"scopes,omitempty"`
+	StoredAt        time.Time  `json:"storedAt"`
+	Store           string     `json:"credentialStore,omitempty"`
+	ExpiresAt       *time.Time `json:"expiresAt,omitempty"`
+	DaysUntilExpiry *int       `json:"daysUntilExpiry,omitempty"` // negative once expired
+}
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Check API connection status",
 	Long:  "Test the connection to the API server and validate the stored API key.",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		migrateLegacyAPIKey()
+
 		creds, err := auth.LoadCredentials()
+		if err != nil && !errors.Is(err, auth.ErrNoCredentials) {
+			return err
+		}
 		if err != nil {
-			fmt.Printf("❌ %s\n", color.RedString("Not authenticated"))
-			fmt.Printf("Run 'leanmcp-cli auth login --api-key <your-key>' to authenticate.\n")
+			statusf("❌ %s (profile %s)\n", color.RedString("Not authenticated"), config.ActiveProfile())
+			statusf("Run 'leanmcp-cli auth login --api-key <your-key>' to authenticate.\n")
 			return nil
 		}
 
-		fmt.Println("🔍 Testing API connection...")
-
-		client := api.NewClient(creds.APIKey)
-		err = client.TestConnection()
+		baseURL, err := config.GetBaseURL()
 		if err != nil {
-			fmt.Printf("❌ %s: %v\n", color.RedString("Connection failed"), err)
-			return nil
+			return err
 		}
 
-		fmt.Printf("✅ %s\n", color.GreenString("API connection successful!"))
-		
-		// Update last used timestamp
-		_ = auth.UpdateLastUsed()
-		
+		statusf("🔍 Testing API connection to %s with profile %s...\n", baseURL, config.ActiveProfile())
+
+		client := api.NewClient(creds.APIKey, baseURL)
+		output := &statusOutput{Profile: config.ActiveProfile(), BaseURL: baseURL, Connected: true}
+		if err := client.TestConnection(); err != nil {
+			output.Connected = false
+			output.Error = err.Error()
+		} else if !creds.External() {
+			// Update last used timestamp
+			_ = auth.UpdateLastUsed()
+		}
+
+		return printer.Print(output, func() {
+			if !output.Connected {
+				fmt.Printf("❌ %s: %v\n", color.RedString("Connection failed"), output.Error)
+				return
+			}
+			fmt.Printf("✅ %s\n", color.GreenString("API connection successful!"))
+		})
+	},
+}
+
+// statusOutput is the machine-readable form of 'auth status'
+type statusOutput struct {
+	Profile   string `json:"profile"`
+	BaseURL   string `json:"baseUrl"`
+	Connected bool   `json:"connected"`
+	Error     string `json:"error,omitempty"`
+}
+
+var listProfilesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List credential profiles",
+	Long: `List the credential profiles in the config file. The current profile,
+used when neither --profile nor LEANMCP_PROFILE is given, is marked with *.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles := config.GetProfiles()
+		return printer.Print(profiles, func() {
+			display.ProfilesTable(profiles)
+		})
+	},
+}
+
+var switchProfileCmd = &cobra.Command{
+	Use:   "switch <profile>",
+	Short: "Change the current profile",
+	Long: `Make a profile the current one, used by later commands when neither
+--profile nor LEANMCP_PROFILE is given.
+
+Example:
+  leanmcp-cli auth switch work
+  leanmcp-cli auth switch default`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := config.ValidateProfileName(name); err != nil {
+			return err
+		}
+		if !config.ProfileExists(name) {
+			return fmt.Errorf("profile %s does not exist; create it with 'leanmcp-cli auth login --profile %s'", name, name)
+		}
+
+		config.SetCurrentProfile(name)
+		if err := config.SaveConfig(); err != nil {
+			return fmt.Errorf("failed to save config: %v", err)
+		}
+
+		statusf("✅ Switched to profile %s\n", color.GreenString(name))
+		if active := config.ActiveProfile(); active != name {
+			statusf("%s --profile or LEANMCP_PROFILE selects profile %s for this shell.\n", color.YellowString("Note:"), active)
+		}
 		return nil
 	},
 }
 
+// readTokenFromStdin reads an API key for 'auth login --with-token', keeping
+// it out of shell history and the process list. On a terminal it is read
+// without echoing it.
+func readTokenFromStdin() (string, error) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprint(os.Stderr, "Paste your API key: ")
+		token, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read API key: %w", err)
+		}
+		return strings.TrimSpace(string(token)), nil
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read API key from stdin: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// promptPassphrase reads the passphrase of the encrypted credentials file
+// from the terminal without echoing it
+func promptPassphrase(confirm bool) (string, error) {
+	if confirm {
+		fmt.Fprintln(os.Stderr, "No OS keyring is available, so your API key will be stored in an encrypted file.")
+		fmt.Fprint(os.Stderr, "Choose a passphrase: ")
+	} else {
+		fmt.Fprint(os.Stderr, "Passphrase for the LeanMCP credentials file: ")
+	}
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if !confirm {
+		return string(passphrase), nil
+	}
+
+	fmt.Fprint(os.Stderr, "Repeat the passphrase: ")
+	repeated, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if string(repeated) != string(passphrase) {
+		return "", errors.New("passphrases do not match")
+	}
+	return string(passphrase), nil
+}
+
+// migrateLegacyAPIKey moves an API key stored in the config file by older
+// versions to the credential store, saying why a passphrase may be asked for
+func migrateLegacyAPIKey() {
+	if !auth.HasLegacyAPIKey() {
+		return
+	}
+
+	statusf("🔐 Moving the API key of profile %s from the config file to the credential store...\n", config.ActiveProfile())
+	store, err := auth.MigrateLegacyCredentials()
+	if err != nil {
+		statusf("%s could not move the API key, it is still used from the config file: %v\n", color.YellowString("Warning:"), err)
+		return
+	}
+	statusf("✅ API key moved to %s\n", auth.StoreDescription(store))
+}
+
+// checkAPIKeyUsable rejects keys the API reports as inactive or expired
+func checkAPIKeyUsable(keyInfo *api.APIKeyInfo) error {
+	if !keyInfo.IsActive {
+		return fmt.Errorf("API key %q is inactive; create a new key in the LeanMCP dashboard", keyInfo.Name)
+	}
+	if keyInfo.ExpiresAt != nil && !keyInfo.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("API key %q expired on %s", keyInfo.Name, keyInfo.ExpiresAt.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// daysUntil returns the number of whole days until t, negative if t has passed
+func daysUntil(t time.Time) int {
+	return int(time.Until(t).Hours() / 24)
+}
+
+// describeExpiry formats a key expiry with the days left, coloring keys that
+// expire within a week
+func describeExpiry(expiresAt *time.Time) string {
+	if expiresAt == nil {
+		return color.GreenString("Never")
+	}
+
+	date := expiresAt.Format("2006-01-02 15:04:05")
+	days := daysUntil(*expiresAt)
+	switch {
+	case !expiresAt.After(time.Now()):
+		return color.RedString("%s (expired)", date)
+	case days == 0:
+		return color.RedString("%s (today)", date)
+	case days == 1:
+		return color.YellowString("%s (in 1 day)", date)
+	case days < 7:
+		return color.YellowString("%s (in %d days)", date, days)
+	default:
+		return fmt.Sprintf("%s (in %d days)", date, days)
+	}
+}
+
 // maskAPIKey masks the API key for display purposes
 func maskAPIKey(apiKey string) string {
 	if len(apiKey) <= 12 {
@@ -145,8 +481,18 @@ func init() {
 	authCmd.AddCommand(logoutCmd)
 	authCmd.AddCommand(whoamiCmd)
 	authCmd.AddCommand(statusCmd)
+	authCmd.AddCommand(listProfilesCmd)
+	authCmd.AddCommand(switchProfileCmd)
 
 	// Login command flags
-	loginCmd.Flags().String("api-key", "", "API key for authentication (required)")
-	loginCmd.MarkFlagRequired("api-key")
+	loginCmd.Flags().String("api-key", "", "API key for authentication")
+	loginCmd.Flags().Bool("with-token", false, "Read the API key from stdin")
+	loginCmd.Flags().String("credential-store", "", "Where to store the API key: auto, keyring or file (default auto)")
+	loginCmd.Flags().Bool("skip-validation", false, "Store the API key without checking it with the API")
+	loginCmd.MarkFlagsMutuallyExclusive("api-key", "with-token")
+
+	// The encrypted credentials file asks for its passphrase on a terminal
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		auth.PromptPassphrase = promptPassphrase
+	}
 }
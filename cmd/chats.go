@@ -25,17 +25,17 @@ var chatsListCmd = &cobra.Command{
 			return err
 		}
 
-		fmt.Println("💬 Fetching chats...")
+		statusf("💬 Fetching chats...\n")
 
 		chats, err := client.ListChats()
 		if err != nil {
 			return fmt.Errorf("failed to list chats: %v", err)
 		}
 
-		fmt.Printf("\nFound %d chat(s):\n\n", len(chats))
-		display.ChatsTable(chats)
-
-		return nil
+		return printer.Print(chats, func() {
+			fmt.Printf("\nFound %d chat(s):\n\n", len(chats))
+			display.ChatsTable(chats)
+		})
 	},
 }
 
@@ -51,16 +51,16 @@ var chatsShowCmd = &cobra.Command{
 		}
 
 		chatID := args[0]
-		fmt.Printf("🔍 Fetching chat %s...\n\n", chatID)
+		statusf("🔍 Fetching chat %s...\n\n", chatID)
 
 		chat, err := client.GetChat(chatID)
 		if err != nil {
 			return fmt.Errorf("failed to get chat: %v", err)
 		}
 
-		display.PrintChat(chat)
-
-		return nil
+		return printer.Print(chat, func() {
+			display.PrintChat(chat)
+		})
 	},
 }
 
@@ -78,7 +78,7 @@ var chatsHistoryCmd = &cobra.Command{
 		chatID := args[0]
 		limit, _ := cmd.Flags().GetInt("limit")
 
-		fmt.Printf("📜 Fetching chat history for %s...\n\n", chatID)
+		statusf("📜 Fetching chat history for %s...\n\n", chatID)
 
 		messages, err := client.GetChatHistory(chatID)
 		if err != nil {
@@ -90,10 +90,10 @@ var chatsHistoryCmd = &cobra.Command{
 			messages = messages[len(messages)-limit:] // Show last N messages
 		}
 
-		fmt.Printf("Showing %d message(s):\n\n", len(messages))
-		display.PrintChatHistory(messages)
-
-		return nil
+		return printer.Print(messages, func() {
+			fmt.Printf("Showing %d message(s):\n\n", len(messages))
+			display.PrintChatHistory(messages)
+		})
 	},
 }
 
@@ -114,7 +114,7 @@ var chatsCreateCmd = &cobra.Command{
 			return fmt.Errorf("--title is required")
 		}
 
-		fmt.Printf("💬 Creating chat '%s'...\n", title)
+		statusf("💬 Creating chat '%s'...\n", title)
 
 		req := api.CreateChatRequest{
 			Title:     title,
@@ -126,10 +126,11 @@ var chatsCreateCmd = &cobra.Command{
 			return fmt.Errorf("failed to create chat: %v", err)
 		}
 
-		fmt.Printf("✅ %s\n\n", color.GreenString("Chat created successfully!"))
-		display.PrintChat(chat)
+		statusf("✅ %s\n\n", color.GreenString("Chat created successfully!"))
 
-		return nil
+		return printer.Print(chat, func() {
+			display.PrintChat(chat)
+		})
 	},
 }
 
@@ -150,18 +151,18 @@ var chatsDeleteCmd = &cobra.Command{
 		force, _ := cmd.Flags().GetBool("force")
 		
 		if !force {
-			fmt.Printf("⚠️  %s\n", color.YellowString("WARNING: This will permanently delete the chat and all messages."))
-			fmt.Printf("Use --force to confirm deletion.\n")
+			statusf("⚠️  %s\n", color.YellowString("WARNING: This will permanently delete the chat and all messages."))
+			statusf("Use --force to confirm deletion.\n")
 			return nil
 		}
 
-		fmt.Printf("🗑️  Deleting chat %s...\n", chatID)
+		statusf("🗑️  Deleting chat %s...\n", chatID)
 
 		if err := client.DeleteChat(chatID); err != nil {
 			return fmt.Errorf("failed to delete chat: %v", err)
 		}
 
-		fmt.Printf("✅ %s\n", color.GreenString("Chat deleted successfully!"))
+		statusf("✅ %s\n", color.GreenString("Chat deleted successfully!"))
 
 		return nil
 	},
@@ -18,8 +18,9 @@ Supports both interactive and flag-based modes:
 - Partial flags: Provide some flags, be prompted for missing ones
 
 The command will:
-1. Create a project record in LeanMCP
-2. Scan the specified directory (respecting .gitignore)
+1. Scan the specified directory (respecting .gitignore and .leanmcpignore)
+   and refuse to continue if files appear to contain secrets
+2. Create a project record in LeanMCP
 3. Create a zip archive of the project files
 4. Upload the zip to S3
 5. Update the project with the S3 location
@@ -35,4 +36,10 @@ func init() {
 	createCmd.Flags().StringP("name", "n", "", "Project name")
 	createCmd.Flags().StringP("description", "d", "", "Project description")
 	createCmd.Flags().StringP("path", "p", "", "Path to project directory (defaults to current directory)")
+	createCmd.Flags().StringSlice("include", []string{}, "Patterns to upload even if ignored (e.g. dist)")
+	createCmd.Flags().StringSlice("exclude", []string{}, "Additional patterns to leave out of the upload")
+	createCmd.Flags().String("symlinks", "", "How to package symlinks: preserve, follow or skip (default preserve)")
+	createCmd.Flags().String("archive-format", "", "Archive format: zip, tar.gz or tar.zst (default: preferred by the backend)")
+	createCmd.Flags().Int("compression-level", 0, "Compression level, 1-9 for zip and tar.gz or 1-22 for tar.zst (default: format default)")
+	createCmd.Flags().Bool("allow-secrets", false, "Upload even if files appear to contain secrets")
 }
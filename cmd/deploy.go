@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ddod/leanmcp-cli/internal/api"
+	"github.com/ddod/leanmcp-cli/internal/config"
+	"github.com/ddod/leanmcp-cli/internal/filesystem"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// deployCmd represents the top-level deploy command
+var deployCmd = &cobra.Command{
+	Use:   "deploy",
+	Short: "Upload and deploy the linked project",
+	Long: `Deploy the project linked to the current directory.
+
+The project is read from .leanmcp/config.json (created by 'leanmcp create').
+The command will:
+1. Scan the project directory (respecting .gitignore and .leanmcpignore)
+   and refuse to continue if files appear to contain secrets
+2. Create a zip archive of the current source and upload it, unless it is
+   identical to the last upload
+3. Update the project with the new S3 location
+4. Run the end-to-end deployment with real-time progress updates
+
+Examples:
+  # Deploy the project in the current directory
+  leanmcp deploy
+
+  # Deploy a project in another directory with a custom port and secrets
+  leanmcp deploy --path ./my-server --port 3000 --secrets secret1,secret2
+
+  # Ship a prebuilt bundle that is excluded by default
+  leanmcp deploy --include dist`,
+	RunE: runDeploy,
+}
+
+func runDeploy(cmd *cobra.Command, args []string) error {
+	projectPath, _ := cmd.Flags().GetString("path")
+	port, _ := cmd.Flags().GetInt("port")
+	secrets, _ := cmd.Flags().GetStringSlice("secrets")
+	forceUpload, _ := cmd.Flags().GetBool("force-upload")
+
+	// Resolve the linked project
+	if projectPath == "" {
+		projectPath = "."
+	}
+
+	projectConfig, err := config.LoadProjectConfig(projectPath)
+	if err != nil {
+		return err
+	}
+	linkedProjectID := projectConfig.Project.ID
+
+	if linkedProjectID == "" {
+		return fmt.Errorf("no project ID found in %s/.leanmcp/config.json", projectPath)
+	}
+
+	if err := filesystem.ValidateDirectory(projectPath); err != nil {
+		return err
+	}
+
+	scanOptions, err := projectScanOptions(cmd, projectPath)
+	if err != nil {
+		return err
+	}
+
+	client, err := getAuthenticatedClient()
+	if err != nil {
+		return fmt.Errorf("authentication failed: %w", err)
+	}
+
+	// Refuse to upload credentials
+	if err := checkForSecrets(cmd, projectPath, scanOptions); err != nil {
+		return err
+	}
+
+	archiveOptions, err := projectArchiveOptions(cmd, client)
+	if err != nil {
+		return err
+	}
+
+	// Package the current source
+	statusf("Packaging project %s...\n", linkedProjectID)
+
+	source, err := createProjectManifest(projectPath, scanOptions)
+	if err != nil {
+		return err
+	}
+	contentHash := source.Manifest.Checksum()
+
+	// Skip the upload when no file changed since the last one
+	unchanged := contentHash == projectConfig.Project.ContentHash && projectConfig.Project.S3Location != ""
+	if unchanged && !forceUpload {
+		statusf("No changes since the last upload, skipping upload (use --force-upload to upload anyway).\n")
+		statusf("\n")
+	} else {
+		project, err := uploadProjectSource(client, linkedProjectID, projectPath, source, scanOptions, archiveOptions)
+		if err != nil {
+			return err
+		}
+
+		// Record the framework for projects created before it was detected
+		framework := project.Framework
+		if framework == "" && projectConfig.Project.Framework == "" {
+			detected, err := filesystem.DetectFramework(projectPath)
+			if err != nil {
+				statusf("⚠️  %s\n", color.YellowString("Could not detect framework: %v", err))
+			} else if detected != nil {
+				framework = detected.Framework
+			}
+		}
+
+		err = config.UpdateProjectConfig(projectPath, config.ProjectInfo{
+			Framework:   framework,
+			Status:      project.Status,
+			S3Location:  project.S3Location,
+			ContentHash: contentHash,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update local config: %w", err)
+		}
+
+		statusf("Upload complete.\n")
+		statusf("\n")
+	}
+
+	request := &api.DeployStreamRequest{
+		ProjectID:     linkedProjectID,
+		ContainerPort: port,
+		SecretIDs:     secrets,
+	}
+
+	return streamDeployment(client, request, verbose)
+}
+
+func init() {
+	rootCmd.AddCommand(deployCmd)
+
+	deployCmd.Flags().StringP("path", "p", "", "Path to project directory (defaults to current directory)")
+	deployCmd.Flags().Int("port", 0, "Container port (defaults to 3001)")
+	deployCmd.Flags().StringSlice("secrets", []string{}, "Comma-separated list of secret IDs to inject")
+	deployCmd.Flags().StringSlice("include", []string{}, "Patterns to upload even if ignored (e.g. dist)")
+	deployCmd.Flags().StringSlice("exclude", []string{}, "Additional patterns to leave out of the upload")
+	deployCmd.Flags().String("symlinks", "", "How to package symlinks: preserve, follow or skip (default preserve)")
+	deployCmd.Flags().String("archive-format", "", "Archive format: zip, tar.gz or tar.zst (default: preferred by the backend)")
+	deployCmd.Flags().Int("compression-level", 0, "Compression level, 1-9 for zip and tar.gz or 1-22 for tar.zst (default: format default)")
+	deployCmd.Flags().Bool("force-upload", false, "Upload even if the files are unchanged since the last upload")
+	deployCmd.Flags().Bool("allow-secrets", false, "Upload even if files appear to contain secrets")
+}
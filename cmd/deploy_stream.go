@@ -49,47 +49,86 @@ func runDeployStream(cmd *cobra.Command, args []string) error {
 		SecretIDs:     secretIDs,
 	}
 
-	fmt.Printf("Starting end-to-end deployment for project: %s\n", projectID)
-	if containerPort > 0 {
-		fmt.Printf("Container port: %d\n", containerPort)
+	// Check if verbose mode is enabled
+	verbose, _ := cmd.Flags().GetBool("verbose")
+
+	return streamDeployment(client, request, verbose)
+}
+
+// deployOutput is the machine-readable form of 'deploy' and 'deploy-stream'
+type deployOutput struct {
+	ProjectID    string `json:"projectId"`
+	DeploymentID string `json:"deploymentId,omitempty"`
+	BuildID      string `json:"buildId,omitempty"`
+	URL          string `json:"url,omitempty"`
+	Status       string `json:"status"`
+}
+
+// streamDeployment runs an end-to-end deployment, renders its progress and
+// prints the result
+func streamDeployment(client *api.Client, request *api.DeployStreamRequest, verbose bool) error {
+	statusf("Starting end-to-end deployment for project: %s\n", request.ProjectID)
+	if request.ContainerPort > 0 {
+		statusf("Container port: %d\n", request.ContainerPort)
 	}
-	if len(secretIDs) > 0 {
-		fmt.Printf("Secrets: %v\n", secretIDs)
+	if len(request.SecretIDs) > 0 {
+		statusf("Secrets: %v\n", request.SecretIDs)
 	}
-	fmt.Println("Connecting to deployment stream...")
+	statusf("Connecting to deployment stream...\n")
 
-	// Check if verbose mode is enabled
-	verbose, _ := cmd.Flags().GetBool("verbose")
 	if verbose {
-		fmt.Printf("Debug: Request payload: %+v\n", request)
+		statusf("Debug: Request payload: %+v\n", request)
 	}
-	fmt.Println()
+	statusf("\n")
 
 	// Start streaming deployment
-	err = client.DeployAndStream(request, func(update *api.StreamUpdate) error {
+	result := &deployOutput{ProjectID: request.ProjectID}
+	err := client.DeployAndStream(request, func(update *api.StreamUpdate) error {
+		result.update(update)
 		return handleStreamUpdate(update, verbose)
 	})
 	if err != nil {
 		return fmt.Errorf("deployment failed: %w", err)
 	}
+	result.Status = "COMPLETED"
 
-	return nil
+	return printer.Print(result, func() {
+		if result.URL != "" {
+			fmt.Printf("Your application is live at: %s\n", result.URL)
+		}
+		if result.DeploymentID != "" {
+			fmt.Printf("Deployment ID: %s\n", result.DeploymentID)
+		}
+	})
+}
+
+// update records the identifiers reported by a stream update
+func (o *deployOutput) update(update *api.StreamUpdate) {
+	if update.DeploymentID != "" {
+		o.DeploymentID = update.DeploymentID
+	}
+	if update.BuildID != "" {
+		o.BuildID = update.BuildID
+	}
+	if update.DeploymentURL != "" {
+		o.URL = update.DeploymentURL
+	}
 }
 
 // handleStreamUpdate processes each streaming update from the server
 func handleStreamUpdate(update *api.StreamUpdate, verbose bool) error {
 	if verbose {
-		fmt.Printf("Debug: Raw update: %+v\n", update)
+		statusf("Debug: Raw update: %+v\n", update)
 	}
 	// Check for failure conditions first
 	if update.BuildStatus == "failed" || update.CurrentStep == "FAILED" {
 		errorMsg := getErrorMessage(update)
-		fmt.Printf("\n Build failed: %s\n", errorMsg)
+		statusf("\n Build failed: %s\n", errorMsg)
 
 		// Note: Build logs API endpoint not available (returns 404)
 		if update.BuildID != "" {
-			fmt.Printf("Build ID: %s\n", update.BuildID)
-			fmt.Printf("Contact support with this Build ID for detailed logs\n")
+			statusf("Build ID: %s\n", update.BuildID)
+			statusf("Contact support with this Build ID for detailed logs\n")
 		}
 
 		return fmt.Errorf("build failed: %s", errorMsg)
@@ -100,52 +139,46 @@ func handleStreamUpdate(update *api.StreamUpdate, verbose bool) error {
 	case "BUILDING":
 		// Show building progress
 		progressBar := createProgressBar(int(update.Progress))
-		fmt.Printf("\r%s %s (%.1f%%) - %s",
+		statusf("\r%s %s (%.1f%%) - %s",
 			getStepIcon(update.CurrentStep),
 			update.CurrentStep,
 			update.Progress,
 			progressBar)
 
 		if update.EstimatedTimeRemaining > 0 {
-			fmt.Printf(" - ETA: %ds", update.EstimatedTimeRemaining)
+			statusf(" - ETA: %ds", update.EstimatedTimeRemaining)
 		}
 
 	case "DEPLOYING":
 		progressBar := createProgressBar(int(update.Progress))
-		fmt.Printf("\r%s %s (%.1f%%) - %s",
+		statusf("\r%s %s (%.1f%%) - %s",
 			getStepIcon(update.CurrentStep),
 			update.CurrentStep,
 			update.Progress,
 			progressBar)
 
 		if update.EstimatedTimeRemaining > 0 {
-			fmt.Printf(" - ETA: %ds", update.EstimatedTimeRemaining)
+			statusf(" - ETA: %ds", update.EstimatedTimeRemaining)
 		}
 
 	case "COMPLETED", "COMPLETE":
-		fmt.Printf("\n Deployment completed successfully!\n")
-		if update.DeploymentURL != "" {
-			fmt.Printf("Your application is live at: %s\n", update.DeploymentURL)
-		}
-		if update.DeploymentID != "" {
-			fmt.Printf("Deployment ID: %s\n", update.DeploymentID)
-		}
+		statusf("\n Deployment completed successfully!\n")
 
 	default:
 		// Handle any other steps or show progress
 		if update.Progress > 0 {
 			progressBar := createProgressBar(int(update.Progress))
-			fmt.Printf("\r%s %s (%.1f%%) - %s",
+			statusf("\r%s %s (%.1f%%) - %s",
 				getStepIcon(update.CurrentStep),
 				update.CurrentStep,
 				update.Progress,
 				progressBar)
 
 			if update.EstimatedTimeRemaining > 0 {
-				fmt.Printf(" - ETA: %ds", update.EstimatedTimeRemaining)
+				statusf(" - ETA: %ds", update.EstimatedTimeRemaining)
 			}
 		} else if update.Message != "" {
-			fmt.Printf("%s\n", update.Message)
+			statusf("%s\n", update.Message)
 		}
 	}
 
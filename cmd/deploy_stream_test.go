@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ddod/leanmcp-cli/internal/api"
+	"github.com/ddod/leanmcp-cli/internal/display"
+)
+
+func TestStreamDeploymentPrintsResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"type\":\"progress\",\"deploymentId\":\"dep-1\",\"buildId\":\"b-1\",\"currentStep\":\"BUILDING\",\"progress\":50}\n\n")
+		fmt.Fprint(w, "data: {\"type\":\"complete\",\"currentStep\":\"COMPLETED\",\"deploymentUrl\":\"https://dep-1.test\"}\n\n")
+	}))
+	defer server.Close()
+
+	resetPrinter(t)
+	var out bytes.Buffer
+	printer = &display.Printer{Format: display.FormatJSON, Out: &out}
+
+	client := api.NewClient("airtrain_test", server.URL)
+	if err := streamDeployment(client, &api.DeployStreamRequest{ProjectID: "p1"}, false); err != nil {
+		t.Fatalf("streamDeployment() error = %v", err)
+	}
+
+	var got deployOutput
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("stdout is not a single JSON result: %v\n%s", err, out.String())
+	}
+	want := deployOutput{ProjectID: "p1", DeploymentID: "dep-1", BuildID: "b-1", URL: "https://dep-1.test", Status: "COMPLETED"}
+	if got != want {
+		t.Errorf("result = %+v, want %+v", got, want)
+	}
+}
@@ -1,8 +1,17 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/ddod/leanmcp-cli/internal/api"
+	"github.com/ddod/leanmcp-cli/internal/display"
 	"github.com/spf13/cobra"
 )
 
@@ -15,11 +24,33 @@ var deploymentsCmd = &cobra.Command{
 var deploymentsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List deployments",
-	Long:  "List all deployments associated with your account",
+	Long: `List all deployments associated with your account.
+
+Use --project to only show deployments of a single project.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println(" Deployments feature coming soon...")
-		fmt.Println("This command will list all your deployments.")
-		return nil
+		client, err := getAuthenticatedClient()
+		if err != nil {
+			return handleAuthError(err)
+		}
+
+		projectFilter, _ := cmd.Flags().GetString("project")
+
+		if projectFilter != "" {
+			statusf("🚀 Fetching deployments for project %s...\n", projectFilter)
+		} else {
+			statusf("🚀 Fetching deployments...\n")
+		}
+
+		deployments, err := client.ListDeployments(projectFilter)
+		if err != nil {
+			handleAPIError(err, "list deployments")
+			return nil
+		}
+
+		return printer.Print(deployments, func() {
+			fmt.Printf("\nFound %d deployment(s):\n\n", len(deployments))
+			display.DeploymentsTable(deployments)
+		})
 	},
 }
 
@@ -29,27 +60,216 @@ var deploymentsShowCmd = &cobra.Command{
 	Long:  "Display detailed information about a specific deployment",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := getAuthenticatedClient()
+		if err != nil {
+			return handleAuthError(err)
+		}
+
 		deploymentID := args[0]
-		fmt.Printf("🔍 Deployment details for %s coming soon...\n", deploymentID)
-		return nil
+		statusf("🔍 Fetching deployment %s...\n\n", deploymentID)
+
+		deployment, err := client.GetDeployment(deploymentID)
+		if err != nil {
+			handleAPIError(err, "get deployment details")
+			return nil
+		}
+
+		return printer.Print(deployment, func() {
+			display.PrintDeployment(deployment)
+		})
 	},
 }
 
 var deploymentsLogsCmd = &cobra.Command{
 	Use:   "logs <deployment-id>",
 	Short: "Show deployment logs",
-	Long:  "Display logs for a specific deployment",
-	Args:  cobra.ExactArgs(1),
+	Long: `Display runtime logs for a specific deployment.
+
+Use --follow to keep streaming new log lines as they are written. The
+connection is re-established automatically if it drops.
+
+Examples:
+  # Last 100 lines
+  leanmcp deployments logs dep_123 --tail 100
+
+  # Stream warnings and errors from the last 10 minutes
+  leanmcp deployments logs dep_123 --follow --since 10m --level warn
+
+  # Only lines matching a regular expression
+  leanmcp deployments logs dep_123 --grep 'tool(s)? call'`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		client, err := getAuthenticatedClient()
+		if err != nil {
+			return handleAuthError(err)
+		}
+
 		deploymentID := args[0]
-		fmt.Printf("📋 Deployment logs for %s coming soon...\n", deploymentID)
+		follow, _ := cmd.Flags().GetBool("follow")
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		tail, _ := cmd.Flags().GetInt("tail")
+		level, _ := cmd.Flags().GetString("level")
+		pattern, _ := cmd.Flags().GetString("grep")
+
+		opts := api.DeploymentLogsOptions{Tail: tail}
+		if sinceFlag != "" {
+			opts.Since, err = parseSince(sinceFlag)
+			if err != nil {
+				return err
+			}
+		}
+
+		filter, err := newLogFilter(level, pattern)
+		if err != nil {
+			return err
+		}
+
+		if !follow {
+			statusf("📋 Fetching logs for deployment %s...\n\n", deploymentID)
+
+			logs, err := client.GetDeploymentLogs(deploymentID, opts)
+			if err != nil {
+				handleAPIError(err, "get deployment logs")
+				return nil
+			}
+
+			entries := filter.apply(logs.Logs)
+			if tail > 0 && len(entries) > tail {
+				entries = entries[len(entries)-tail:]
+			}
+
+			return printer.Print(entries, func() {
+				display.PrintDeploymentLogs(entries)
+			})
+		}
+
+		// Followed lines are written one at a time, so only formats that
+		// can be streamed line by line are supported
+		if printer.Format != display.FormatTable && printer.Format != display.FormatJSON {
+			return fmt.Errorf("--follow supports table and json output only")
+		}
+
+		statusf("📋 Following logs for deployment %s (Ctrl+C to stop)...\n\n", deploymentID)
+		encoder := json.NewEncoder(printer.Out)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		err = client.FollowDeploymentLogs(ctx, deploymentID, opts, func(entry api.DeploymentLogEntry) error {
+			if !filter.matches(entry) {
+				return nil
+			}
+			if printer.Format == display.FormatJSON {
+				// One JSON object per line
+				return encoder.Encode(entry)
+			}
+			display.PrintDeploymentLogEntry(entry)
+			return nil
+		})
+		if err != nil {
+			handleAPIError(err, "get deployment logs")
+		}
+
 		return nil
 	},
 }
 
+// logLevels orders log levels by severity for --level filtering
+var logLevels = map[string]int{
+	"trace":   0,
+	"debug":   1,
+	"info":    2,
+	"warn":    3,
+	"warning": 3,
+	"error":   4,
+	"fatal":   5,
+}
+
+// logFilter selects deployment log lines by minimum level and regex
+type logFilter struct {
+	minLevel int
+	pattern  *regexp.Regexp
+}
+
+// newLogFilter builds a filter from the --level and --grep flags
+func newLogFilter(level, pattern string) (*logFilter, error) {
+	filter := &logFilter{minLevel: -1}
+
+	if level != "" {
+		severity, ok := logLevels[strings.ToLower(level)]
+		if !ok {
+			return nil, fmt.Errorf("invalid --level %q (use trace, debug, info, warn, error or fatal)", level)
+		}
+		filter.minLevel = severity
+	}
+
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --grep pattern: %v", err)
+		}
+		filter.pattern = re
+	}
+
+	return filter, nil
+}
+
+// matches reports whether a log entry passes the filter
+func (f *logFilter) matches(entry api.DeploymentLogEntry) bool {
+	if f.minLevel >= 0 {
+		severity, ok := logLevels[strings.ToLower(entry.Level)]
+		if !ok {
+			severity = logLevels["info"]
+		}
+		if severity < f.minLevel {
+			return false
+		}
+	}
+
+	if f.pattern != nil && !f.pattern.MatchString(entry.Message) {
+		return false
+	}
+
+	return true
+}
+
+// apply returns the entries that pass the filter
+func (f *logFilter) apply(entries []api.DeploymentLogEntry) []api.DeploymentLogEntry {
+	matched := []api.DeploymentLogEntry{}
+	for _, entry := range entries {
+		if f.matches(entry) {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
+
+// parseSince accepts a relative duration (10m, 2h) or an RFC3339 timestamp
+func parseSince(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid --since %q (use a duration like 10m or an RFC3339 timestamp)", value)
+}
+
 func init() {
 	rootCmd.AddCommand(deploymentsCmd)
 	deploymentsCmd.AddCommand(deploymentsListCmd)
 	deploymentsCmd.AddCommand(deploymentsShowCmd)
 	deploymentsCmd.AddCommand(deploymentsLogsCmd)
+
+	// List command flags
+	deploymentsListCmd.Flags().String("project", "", "Only show deployments for this project ID")
+
+	// Logs command flags
+	deploymentsLogsCmd.Flags().BoolP("follow", "f", false, "Stream new log lines as they are written")
+	deploymentsLogsCmd.Flags().String("since", "", "Only show lines newer than a duration (10m) or RFC3339 timestamp")
+	deploymentsLogsCmd.Flags().Int("tail", 0, "Only show the last N lines (0 = all)")
+	deploymentsLogsCmd.Flags().String("level", "", "Minimum log level to show (debug, info, warn, error)")
+	deploymentsLogsCmd.Flags().String("grep", "", "Only show lines whose message matches this regular expression")
 }
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ddod/leanmcp-cli/internal/display"
+	"github.com/ddod/leanmcp-cli/internal/filesystem"
+	"github.com/spf13/cobra"
+)
+
+var filesCmd = &cobra.Command{
+	Use:   "files",
+	Short: "Inspect which files are uploaded",
+	Long: `Commands for checking what 'leanmcp create' and 'leanmcp deploy' will package.
+
+Both commands apply the same rules as an upload: built-in excludes,
+.git/info/exclude, .gitignore, .leanmcpignore, the files section of
+.leanmcp/config.json and the --include/--exclude flags.`,
+}
+
+// fileEntry is the machine-readable form of a file in the upload
+type fileEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Mode   string `json:"mode"`
+	Target string `json:"target,omitempty"` // set for symlinks stored as links
+}
+
+// checkIgnoreOutput is the machine-readable form of an ignore explanation
+type checkIgnoreOutput struct {
+	Path    string `json:"path"`
+	Ignored bool   `json:"ignored"`
+	Parent  string `json:"parent,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+}
+
+var filesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the files that would be uploaded",
+	Long: `Dry run of the upload: list every file that would be packaged with its
+size, followed by the total.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath, _ := cmd.Flags().GetString("path")
+
+		if err := filesystem.ValidateDirectory(projectPath); err != nil {
+			return err
+		}
+
+		scanOptions, err := projectScanOptions(cmd, projectPath)
+		if err != nil {
+			return err
+		}
+
+		zipper := filesystem.NewProjectZipperWithOptions(projectPath, scanOptions)
+		files, stats, err := zipper.PreviewFiles(0)
+		if err != nil {
+			return fmt.Errorf("failed to scan directory: %w", err)
+		}
+		printScanWarnings(zipper.Warnings())
+
+		entries := make([]fileEntry, 0, len(files))
+		for _, file := range files {
+			entries = append(entries, fileEntry{
+				Path:   filepath.ToSlash(file.RelPath),
+				Size:   file.Size,
+				Mode:   file.Mode.String(),
+				Target: filepath.ToSlash(file.LinkTarget),
+			})
+		}
+
+		return printer.Print(entries, func() {
+			display.FilesTable(files, stats)
+		})
+	},
+}
+
+var filesCheckIgnoreCmd = &cobra.Command{
+	Use:   "check-ignore <path>...",
+	Short: "Explain why a path is or is not uploaded",
+	Long: `Show which rule decides whether a path is uploaded: a built-in exclude,
+a line of a .gitignore or .leanmcpignore file, .git/info/exclude, the project
+config or an --include/--exclude flag. Paths are relative to the current
+directory and do not have to exist; end a path with / to check a directory.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectPath, _ := cmd.Flags().GetString("path")
+
+		if err := filesystem.ValidateDirectory(projectPath); err != nil {
+			return err
+		}
+
+		scanOptions, err := projectScanOptions(cmd, projectPath)
+		if err != nil {
+			return err
+		}
+
+		root, err := filepath.Abs(projectPath)
+		if err != nil {
+			return fmt.Errorf("invalid project path: %w", err)
+		}
+
+		zipper := filesystem.NewProjectZipperWithOptions(root, scanOptions)
+
+		var results []*filesystem.IgnoreResult
+		for _, arg := range args {
+			relPath, err := projectRelativePath(root, arg)
+			if err != nil {
+				return err
+			}
+
+			result, err := zipper.CheckIgnore(relPath)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+
+		outputs := make([]checkIgnoreOutput, 0, len(results))
+		for _, result := range results {
+			output := checkIgnoreOutput{
+				Path:    result.Path,
+				Ignored: result.Ignored,
+				Parent:  result.Parent,
+			}
+			if result.Rule != nil {
+				output.Source = result.Rule.Source
+				output.Line = result.Rule.Line
+				output.Pattern = result.Rule.Pattern
+			}
+			outputs = append(outputs, output)
+		}
+
+		return printer.Print(outputs, func() {
+			for _, result := range results {
+				display.PrintIgnoreResult(result)
+			}
+		})
+	},
+}
+
+// projectRelativePath converts a path given on the command line to a path
+// relative to the project root, keeping a trailing slash
+func projectRelativePath(root, arg string) (string, error) {
+	absPath, err := filepath.Abs(arg)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %s: %w", arg, err)
+	}
+
+	relPath, err := filepath.Rel(root, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s is not inside the project directory", arg)
+	}
+
+	if last := arg[len(arg)-1]; last == '/' || last == filepath.Separator {
+		relPath += "/"
+	}
+
+	return relPath, nil
+}
+
+func init() {
+	rootCmd.AddCommand(filesCmd)
+	filesCmd.AddCommand(filesListCmd)
+	filesCmd.AddCommand(filesCheckIgnoreCmd)
+
+	// Flags shared with create and deploy so the same rules can be previewed
+	for _, c := range []*cobra.Command{filesListCmd, filesCheckIgnoreCmd} {
+		c.Flags().StringP("path", "p", ".", "Path to project directory")
+		c.Flags().StringSlice("include", []string{}, "Patterns to upload even if ignored (e.g. dist)")
+		c.Flags().StringSlice("exclude", []string{}, "Additional patterns to leave out of the upload")
+	}
+	filesListCmd.Flags().String("symlinks", "", "How to package symlinks: preserve, follow or skip (default preserve)")
+}
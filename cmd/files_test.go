@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProjectRelativePath(t *testing.T) {
+	root := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	tests := []struct {
+		arg     string
+		want    string
+		wantErr bool
+	}{
+		{arg: "main.go", want: "main.go"},
+		{arg: "src/server.ts", want: filepath.Join("src", "server.ts")},
+		{arg: "dist/", want: "dist/"},
+		{arg: "./a/../b", want: "b"},
+		{arg: filepath.Join(root, "abs.txt"), want: "abs.txt"},
+		{arg: ".", want: "."},
+		{arg: "..foo", want: "..foo"},
+		{arg: "..", wantErr: true},
+		{arg: "../other/file.txt", wantErr: true},
+		{arg: filepath.Dir(root), wantErr: true},
+		{arg: "a/../../x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := projectRelativePath(root, tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("projectRelativePath(%q) error = %v, want error: %v", tt.arg, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("projectRelativePath(%q) = %q, want %q", tt.arg, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ddod/leanmcp-cli/internal/config"
+	"github.com/ddod/leanmcp-cli/internal/display"
+)
+
+// outputFormat holds the value of the global --output flag
+var outputFormat string
+
+// printer renders command results in the format selected with --output
+var printer = &display.Printer{Format: display.FormatTable, Out: os.Stdout}
+
+// initPrinter validates --output and configures the shared printer. Without
+// --output, the output setting of the active profile is used.
+func initPrinter() error {
+	format := outputFormat
+	if format == "" {
+		format = config.GetProfileString("output")
+	}
+	p, err := display.NewPrinter(format)
+	if err != nil {
+		return err
+	}
+	printer = p
+	return nil
+}
+
+// statusOut is where progress and status messages are written. With a
+// machine-readable format they go to stderr so stdout stays parseable.
+func statusOut() io.Writer {
+	if printer.IsTable() {
+		return os.Stdout
+	}
+	return os.Stderr
+}
+
+// statusf prints a progress or status message
+func statusf(format string, a ...interface{}) {
+	fmt.Fprintf(statusOut(), format, a...)
+}
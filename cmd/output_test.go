@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ddod/leanmcp-cli/internal/config"
+	"github.com/ddod/leanmcp-cli/internal/display"
+	"github.com/spf13/viper"
+)
+
+// resetPrinter restores the shared printer and --output after a test
+func resetPrinter(t *testing.T) {
+	t.Helper()
+	saved, savedFormat := printer, outputFormat
+	t.Cleanup(func() {
+		printer, outputFormat = saved, savedFormat
+		viper.Reset()
+	})
+	viper.Reset()
+}
+
+func TestInitPrinter(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    string
+		profile string // output setting of the active profile
+		want    display.Format
+		wantErr bool
+	}{
+		{name: "default", want: display.FormatTable},
+		{name: "flag", flag: "json", want: display.FormatJSON},
+		{name: "profile setting", profile: "yaml", want: display.FormatYAML},
+		{name: "flag wins over profile", flag: "csv", profile: "yaml", want: display.FormatCSV},
+		{name: "template", flag: "template={{.ID}}", want: display.FormatTemplate},
+		{name: "unknown flag", flag: "xml", wantErr: true},
+		{name: "unknown profile setting", profile: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetPrinter(t)
+			outputFormat = tt.flag
+			if tt.profile != "" {
+				config.SetProfileString("output", tt.profile)
+			}
+
+			err := initPrinter()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("initPrinter() error = %v, want error: %v", err, tt.wantErr)
+			}
+			if err == nil && printer.Format != tt.want {
+				t.Errorf("printer format = %q, want %q", printer.Format, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusOut(t *testing.T) {
+	tests := map[string]*os.File{
+		"table":          os.Stdout,
+		"json":           os.Stderr,
+		"yaml":           os.Stderr,
+		"csv":            os.Stderr,
+		"jsonpath={.id}": os.Stderr,
+	}
+
+	for format, want := range tests {
+		t.Run(format, func(t *testing.T) {
+			resetPrinter(t)
+			outputFormat = format
+			if err := initPrinter(); err != nil {
+				t.Fatal(err)
+			}
+			if got := statusOut(); got != want {
+				t.Errorf("statusOut() = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
+	"slices"
 	"strings"
 
 	"github.com/ddod/leanmcp-cli/internal/api"
@@ -10,6 +15,7 @@ import (
 	"github.com/ddod/leanmcp-cli/internal/filesystem"
 	"github.com/ddod/leanmcp-cli/internal/interactive"
 	"github.com/ddod/leanmcp-cli/internal/auth"
+	"github.com/ddod/leanmcp-cli/internal/upload"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
@@ -27,11 +33,10 @@ var projectsListCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := getAuthenticatedClient()
 		if err != nil {
-			handleAuthError()
-			return nil
+			return handleAuthError(err)
 		}
 
-		fmt.Println("📋 Fetching projects...")
+		statusf("📋 Fetching projects...\n")
 
 		projects, err := client.ListProjects()
 		if err != nil {
@@ -39,10 +44,10 @@ var projectsListCmd = &cobra.Command{
 			return nil // Return nil to prevent usage help from showing
 		}
 
-		fmt.Printf("\nFound %d project(s):\n\n", len(projects))
-		display.ProjectsTable(projects)
-
-		return nil
+		return printer.Print(projects, func() {
+			fmt.Printf("\nFound %d project(s):\n\n", len(projects))
+			display.ProjectsTable(projects)
+		})
 	},
 }
 
@@ -58,7 +63,7 @@ var projectsShowCmd = &cobra.Command{
 		}
 
 		projectID := args[0]
-		fmt.Printf("🔍 Fetching project %s...\n\n", projectID)
+		statusf("🔍 Fetching project %s...\n\n", projectID)
 
 		project, err := client.GetProject(projectID)
 		if err != nil {
@@ -66,9 +71,9 @@ var projectsShowCmd = &cobra.Command{
 			return nil
 		}
 
-		display.PrintProject(project)
-
-		return nil
+		return printer.Print(project, func() {
+			display.PrintProject(project)
+		})
 	},
 }
 
@@ -83,8 +88,9 @@ Supports both interactive and flag-based modes:
 - Partial flags: Provide some flags, be prompted for missing ones
 
 The command will:
-1. Create a project record in LeanMCP
-2. Scan the specified directory (respecting .gitignore)
+1. Scan the specified directory (respecting .gitignore and .leanmcpignore)
+   and refuse to continue if files appear to contain secrets
+2. Create a project record in LeanMCP
 3. Create a zip archive of the project files
 4. Upload the zip to S3
 5. Update the project with the S3 location
@@ -100,11 +106,17 @@ The command will:
 		description, _ := cmd.Flags().GetString("description")
 		projectPath, _ := cmd.Flags().GetString("path")
 
+		scanOptions, err := flagScanOptions(cmd)
+		if err != nil {
+			return err
+		}
+
 		// Collect project information
 		flow := &interactive.ProjectCreationFlow{
 			Name:        name,
 			Description: description,
 			Path:        projectPath,
+			ScanOptions: scanOptions,
 		}
 
 		err = flow.CollectProjectInfo()
@@ -112,70 +124,78 @@ The command will:
 			return err
 		}
 
-		// Create project record
-		fmt.Printf("Creating project '%s'...\n", flow.Name)
-		
-		createReq := api.CreateProjectRequest{
-			Name:        flow.Name,
-			Description: flow.Description,
-		}
-
-		project, err := client.CreateProject(createReq)
+		// Refuse to upload credentials before anything is created
+		err = checkForSecrets(cmd, flow.Path, flow.ScanOptions)
 		if err != nil {
-			return fmt.Errorf("failed to create project: %w", err)
+			return err
 		}
 
-		// Scan and zip files
-		fmt.Printf("Processing %d files...\n", flow.Stats.TotalFiles)
-		
-		zipper := filesystem.NewProjectZipper(flow.Path)
-		zipResult, err := zipper.CreateZip()
+		archiveOptions, err := projectArchiveOptions(cmd, client)
 		if err != nil {
-			return fmt.Errorf("failed to create zip: %w", err)
+			return err
 		}
 
-		// Validate zip size
-		err = filesystem.ValidateZipSize(zipResult.Data)
-		if err != nil {
-			return fmt.Errorf("zip validation failed: %w", err)
+		// Reuse the project record of an interrupted upload from this directory
+		project := resumableProject(client, flow.Path)
+		if project != nil {
+			statusf("Resuming project '%s' (%s)...\n", project.Name, project.ID)
+		} else {
+			// Create project record
+			statusf("Creating project '%s'...\n", flow.Name)
+
+			createReq := api.CreateProjectRequest{
+				Name:        flow.Name,
+				Description: flow.Description,
+			}
+			if flow.Framework != nil {
+				statusf("Detected %s\n", flow.Framework)
+			}
+			createReq.SetFramework(flow.Framework)
+
+			project, err = client.CreateProject(createReq)
+			if err != nil {
+				return fmt.Errorf("failed to create project: %w", err)
+			}
 		}
 
-		// Upload to S3
-		fmt.Println("Uploading files...")
-		
-		uploadResp, err := client.GetUploadURL(project.ID, "project.zip", int64(len(zipResult.Data)))
+		// Scan, zip and upload files
+		statusf("Processing %d files...\n", flow.Stats.TotalFiles)
+
+		source, err := createProjectManifest(flow.Path, flow.ScanOptions)
 		if err != nil {
-			return fmt.Errorf("failed to get upload URL: %w", err)
+			return err
 		}
 
-		err = client.UploadToS3(uploadResp.URL, zipResult.Data)
+		updatedProject, err := uploadProjectSource(client, project.ID, flow.Path, source, flow.ScanOptions, archiveOptions)
 		if err != nil {
-			return fmt.Errorf("failed to upload to S3: %w", err)
+			return err
 		}
 
-		// Update project record
-		updatedProject, err := client.UpdateS3Location(project.ID, uploadResp.S3Location)
-		if err != nil {
-			return fmt.Errorf("failed to update S3 location: %w", err)
+		// Keep the detected framework if the backend does not echo it
+		if updatedProject.Framework == "" && flow.Framework != nil {
+			updatedProject.Framework = flow.Framework.Framework
 		}
 
-		// Save local configuration
+		// Save local configuration, remembering what was uploaded
 		err = config.SaveProjectConfig(flow.Path, updatedProject)
+		if err == nil {
+			err = config.UpdateProjectConfig(flow.Path, config.ProjectInfo{ContentHash: source.Manifest.Checksum()})
+		}
 		if err != nil {
 			return fmt.Errorf("failed to save local config: %w", err)
 		}
 
 		// Success
-		fmt.Printf("\n✅ Project '%s' created successfully!\n", flow.Name)
-		fmt.Println("\nNext steps:")
-		fmt.Println("  leanmcp-cli build")
-		fmt.Println("  leanmcp-cli deploy")
+		statusf("\n✅ Project '%s' created successfully!\n", flow.Name)
 
-		// Show project summary
-		fmt.Println("\n" + color.GreenString("Project Details:"))
-		display.PrintProject(updatedProject)
+		return printer.Print(updatedProject, func() {
+			fmt.Println("\nNext steps:")
+			fmt.Println("  leanmcp deploy")
 
-		return nil
+			// Show project summary
+			fmt.Println("\n" + color.GreenString("Project Details:"))
+			display.PrintProject(updatedProject)
+		})
 	},
 }
 
@@ -196,18 +216,18 @@ var projectsDeleteCmd = &cobra.Command{
 		force, _ := cmd.Flags().GetBool("force")
 		
 		if !force {
-			fmt.Printf("⚠️  %s\n", color.YellowString("WARNING: This will permanently delete the project and all associated data."))
-			fmt.Printf("Use --force to confirm deletion.\n")
+			statusf("⚠️  %s\n", color.YellowString("WARNING: This will permanently delete the project and all associated data."))
+			statusf("Use --force to confirm deletion.\n")
 			return nil
 		}
 
-		fmt.Printf("🗑️  Deleting project %s...\n", projectID)
+		statusf("🗑️  Deleting project %s...\n", projectID)
 
 		if err := client.DeleteProject(projectID); err != nil {
 			return fmt.Errorf("failed to delete project: %v", err)
 		}
 
-		fmt.Printf("✅ %s\n", color.GreenString("Project deleted successfully!"))
+		statusf("✅ %s\n", color.GreenString("Project deleted successfully!"))
 
 		return nil
 	},
@@ -225,17 +245,17 @@ var projectsBuildsCmd = &cobra.Command{
 		}
 
 		projectID := args[0]
-		fmt.Printf("🔨 Fetching builds for project %s...\n", projectID)
+		statusf("🔨 Fetching builds for project %s...\n", projectID)
 
 		builds, err := client.GetProjectBuilds(projectID)
 		if err != nil {
 			return fmt.Errorf("failed to get project builds: %v", err)
 		}
 
-		fmt.Printf("\nFound %d build(s):\n\n", len(builds))
-		display.BuildsTable(builds)
-
-		return nil
+		return printer.Print(builds, func() {
+			fmt.Printf("\nFound %d build(s):\n\n", len(builds))
+			display.BuildsTable(builds)
+		})
 	},
 }
 
@@ -251,63 +271,344 @@ var projectsBuildCmd = &cobra.Command{
 		}
 
 		projectID := args[0]
-		fmt.Printf("🔨 Starting build for project %s...\n", projectID)
+		statusf("🔨 Starting build for project %s...\n", projectID)
 
 		build, err := client.StartBuild(projectID)
 		if err != nil {
 			return fmt.Errorf("failed to start build: %v", err)
 		}
 
-		fmt.Printf("✅ %s\n", color.GreenString("Build started successfully!"))
-		fmt.Printf("%s %s\n", color.CyanString("Build ID:"), build.ID)
-		fmt.Printf("%s %s\n", color.CyanString("Status:"), build.Status)
-		fmt.Printf("%s %s\n", color.CyanString("Created:"), build.CreatedAt.Format("2006-01-02 15:04:05"))
+		return printer.Print(build, func() {
+			fmt.Printf("✅ %s\n", color.GreenString("Build started successfully!"))
+			fmt.Printf("%s %s\n", color.CyanString("Build ID:"), build.ID)
+			fmt.Printf("%s %s\n", color.CyanString("Status:"), build.Status)
+			fmt.Printf("%s %s\n", color.CyanString("Created:"), build.CreatedAt.Format("2006-01-02 15:04:05"))
+		})
+	},
+}
+
+// checkForSecrets scans the files that would be uploaded for credentials and
+// blocks the upload with a report unless --allow-secrets is set
+func checkForSecrets(cmd *cobra.Command, projectPath string, scanOptions filesystem.ScanOptions) error {
+	allowSecrets, _ := cmd.Flags().GetBool("allow-secrets")
+
+	secretOptions, err := config.ProjectSecretScanOptions(projectPath)
+	if err != nil {
+		return err
+	}
+
+	secretScanner, err := filesystem.NewSecretScanner(secretOptions)
+	if err != nil {
+		return err
+	}
 
+	files, _, err := filesystem.NewDirectoryScannerWithOptions(projectPath, scanOptions).GetFileList()
+	if err != nil {
+		return fmt.Errorf("failed to scan directory: %w", err)
+	}
+
+	result, err := secretScanner.ScanFiles(files)
+	if err != nil {
+		return fmt.Errorf("secret scan failed: %w", err)
+	}
+	if len(result.Skipped) > 0 {
+		statusf("⚠️  %s\n", color.YellowString("%d file(s) larger than %d MB were not scanned for secrets:",
+			len(result.Skipped), filesystem.MaxSecretScanSize/(1024*1024)))
+		for _, path := range result.Skipped {
+			statusf("  %s\n", path)
+		}
+	}
+
+	findings := result.Findings
+	if len(findings) == 0 {
 		return nil
-	},
+	}
+
+	if allowSecrets {
+		statusf("⚠️  %s\n", color.YellowString("%d possible secret(s) found, uploading anyway (--allow-secrets)", len(findings)))
+		return nil
+	}
+
+	statusf("❌ %s\n\n", color.RedString("Possible secrets found in project files:"))
+	for _, finding := range findings {
+		statusf("  %s  %s  %s\n",
+			color.CyanString("%s:%d", finding.Path, finding.Line),
+			finding.Rule,
+			finding.Match)
+	}
+	statusf("\nRemove them or exclude the files in .leanmcpignore. To allow a finding, add\n")
+	statusf("'%s' to the line, list it under \"secrets\" in .leanmcp/config.json,\n", "leanmcp:allow-secret")
+	statusf("or pass --allow-secrets.\n")
+
+	return fmt.Errorf("upload blocked: %d possible secret(s) found", len(findings))
+}
+
+// createProjectManifest scans the project directory and hashes its files
+// without archiving them
+func createProjectManifest(projectPath string, scanOptions filesystem.ScanOptions) (*filesystem.ManifestResult, error) {
+	zipper := filesystem.NewProjectZipperWithOptions(projectPath, scanOptions)
+	source, err := zipper.CreateManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan project: %w", err)
+	}
+	printScanWarnings(source.Warnings)
+
+	return source, nil
+}
+
+// createProjectArchive archives the project directory into a temporary file.
+// The caller must Remove the archive when done.
+func createProjectArchive(projectPath string, scanOptions filesystem.ScanOptions, archiveOptions filesystem.ArchiveOptions) (*filesystem.ZipResult, error) {
+	zipper := filesystem.NewProjectZipperWithOptions(projectPath, scanOptions)
+	zipper.Archive = archiveOptions
+	zipResult, err := zipper.CreateZip()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	// Validate zip size
+	err = filesystem.ValidateZipSize(zipResult.Size)
+	if err != nil {
+		zipResult.Remove()
+		return nil, fmt.Errorf("archive validation failed: %w", err)
+	}
+
+	return zipResult, nil
+}
+
+// uploadProjectSource uploads the project source and points the project
+// record at it. Only changed files are sent when the server supports
+// incremental uploads; otherwise the project is archived and the whole
+// archive is uploaded, resuming an interrupted upload of the same archive.
+func uploadProjectSource(client *api.Client, projectID, projectPath string, source *filesystem.ManifestResult, scanOptions filesystem.ScanOptions, archiveOptions filesystem.ArchiveOptions) (*api.Project, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	uploader := upload.NewUploader(client, projectID, projectPath)
+	uploader.OnResume = func(uploaded, total int) {
+		statusf("Resuming interrupted upload (%d/%d parts already uploaded)...\n", uploaded, total)
+	}
+
+	statusf("Uploading %d files...\n", source.FileCount)
+
+	progress := newUploadProgress()
+	uploader.OnProgress = progress.Update
+
+	result, err := uploader.UploadIncremental(ctx, projectPath, source.Manifest)
+	progress.Finish()
+
+	var s3Location string
+	switch {
+	case err == nil:
+		statusf("Uploaded %d changed file(s) (%s), %d unchanged.\n",
+			result.ChangedFiles,
+			filesystem.GetHumanReadableSize(result.UploadedBytes),
+			result.TotalFiles-result.ChangedFiles)
+		s3Location = result.S3Location
+
+	case errors.Is(err, api.ErrIncrementalUnsupported):
+		// Fall back to uploading the whole archive
+		zipResult, err := createProjectArchive(projectPath, scanOptions, archiveOptions)
+		if err != nil {
+			return nil, err
+		}
+		defer zipResult.Remove()
+
+		statusf("Uploading %s archive (%s)...\n", zipResult.Format, filesystem.GetHumanReadableSize(zipResult.Size))
+
+		progress = newUploadProgress()
+		uploader.OnProgress = progress.Update
+
+		s3Location, err = uploader.Upload(ctx, zipResult)
+		progress.Finish()
+		if err != nil {
+			if state, _ := upload.LoadState(uploader.StatePath); state != nil {
+				statusf("⚠️  %s\n", color.YellowString("Upload interrupted; run the command again to resume it."))
+			}
+			return nil, fmt.Errorf("failed to upload to S3: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("failed to upload files: %w", err)
+	}
+
+	// Update project record
+	updatedProject, err := client.UpdateS3Location(projectID, s3Location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update S3 location: %w", err)
+	}
+
+	return updatedProject, nil
+}
+
+// resumableProject returns the project of an unfinished upload recorded in
+// projectPath, or nil if there is none or the project no longer exists
+func resumableProject(client *api.Client, projectPath string) *api.Project {
+	state, err := upload.LoadState(upload.StatePath(projectPath))
+	if err != nil || state == nil || config.HasProjectConfig(projectPath) {
+		return nil
+	}
+
+	project, err := client.GetProject(state.ProjectID)
+	if err != nil {
+		return nil
+	}
+	return project
+}
+
+// projectScanOptions combines the file settings from the project config with
+// the --include, --exclude and --symlinks flags, which take precedence
+func projectScanOptions(cmd *cobra.Command, projectPath string) (filesystem.ScanOptions, error) {
+	options, err := config.ProjectScanOptions(projectPath)
+	if err != nil {
+		return options, err
+	}
+
+	flagOptions, err := flagScanOptions(cmd)
+	if err != nil {
+		return options, err
+	}
+	options.Include = append(options.Include, flagOptions.Include...)
+	options.Exclude = append(options.Exclude, flagOptions.Exclude...)
+	if flagOptions.Symlinks != "" {
+		options.Symlinks = flagOptions.Symlinks
+	}
+
+	return options, nil
 }
 
+// flagScanOptions reads the --include, --exclude and --symlinks flags.
+// Symlinks is left empty when the flag is not set.
+func flagScanOptions(cmd *cobra.Command) (filesystem.ScanOptions, error) {
+	include, _ := cmd.Flags().GetStringSlice("include")
+	exclude, _ := cmd.Flags().GetStringSlice("exclude")
+
+	options := filesystem.ScanOptions{
+		Include: filesystem.NewPatterns("--include", include),
+		Exclude: filesystem.NewPatterns("--exclude", exclude),
+	}
+
+	if symlinks, _ := cmd.Flags().GetString("symlinks"); symlinks != "" {
+		policy, err := filesystem.ParseSymlinkPolicy(symlinks)
+		if err != nil {
+			return options, err
+		}
+		options.Symlinks = policy
+	}
+
+	return options, nil
+}
+
+// projectArchiveOptions reads the --archive-format and --compression-level
+// flags. Without --archive-format the backend's most preferred format that
+// the CLI can write is used. Backends that do not list their formats get a
+// zip unless another format is requested explicitly.
+func projectArchiveOptions(cmd *cobra.Command, client *api.Client) (filesystem.ArchiveOptions, error) {
+	formatName, _ := cmd.Flags().GetString("archive-format")
+	level, _ := cmd.Flags().GetInt("compression-level")
+
+	options := filesystem.ArchiveOptions{
+		Format:           filesystem.DefaultArchiveFormat,
+		CompressionLevel: level,
+	}
+
+	accepted, err := client.GetUploadFormats(context.Background())
+	if err != nil && !errors.Is(err, api.ErrUploadFormatsUnsupported) {
+		return options, fmt.Errorf("failed to get accepted archive formats: %w", err)
+	}
+
+	var formats []filesystem.ArchiveFormat
+	for _, name := range accepted {
+		if format, err := filesystem.ParseArchiveFormat(name); err == nil {
+			formats = append(formats, format)
+		}
+	}
+
+	if formatName != "" {
+		options.Format, err = filesystem.ParseArchiveFormat(formatName)
+		if err != nil {
+			return options, err
+		}
+		if len(accepted) > 0 && !slices.Contains(formats, options.Format) {
+			return options, fmt.Errorf("the backend does not accept %s archives (accepted: %s)", options.Format, strings.Join(accepted, ", "))
+		}
+	} else if len(formats) > 0 {
+		options.Format = formats[0]
+	}
+
+	return options, options.Validate()
+}
+
+// printScanWarnings reports files that were left out of the upload because
+// they cannot be packaged, such as sockets or broken symlinks
+func printScanWarnings(warnings []filesystem.ScanWarning) {
+	for _, warning := range warnings {
+		statusf("⚠️  %s\n", color.YellowString("Skipped %s", warning))
+	}
+}
+
+// errNotAuthenticated is returned by getAuthenticatedClient when no API key
+// is stored
+var errNotAuthenticated = errors.New("not authenticated. Run 'leanmcp-cli auth login --api-key <your-key>' first")
+
 // getAuthenticatedClient creates an authenticated API client
 func getAuthenticatedClient() (*api.Client, error) {
 	creds, err := auth.LoadCredentials()
+	if errors.Is(err, auth.ErrNoCredentials) {
+		return nil, errNotAuthenticated
+	}
+	if err != nil {
+		return nil, err
+	}
+	if creds.Store == "" {
+		statusf("%s the API key is still stored in the config file; run 'leanmcp-cli auth status' to move it to the credential store.\n", color.YellowString("Note:"))
+	}
+
+	baseURL, err := config.GetBaseURL()
 	if err != nil {
-		return nil, fmt.Errorf("not authenticated. Run 'leanmcp-cli auth login --api-key <your-key>' first")
+		return nil, err
 	}
 
-	return api.NewClient(creds.APIKey), nil
+	return api.NewClient(creds.APIKey, baseURL), nil
 }
 
-// handleAuthError handles authentication errors with user-friendly messages
-func handleAuthError() {
-	fmt.Printf("❌ %s\n", color.RedString("Not authenticated"))
-	fmt.Printf("Please run: %s\n", color.CyanString("leanmcp-cli auth login --api-key <your-key>"))
+// handleAuthError handles authentication errors with user-friendly messages.
+// Errors other than a missing API key, such as a credential store that cannot
+// be read, are returned.
+func handleAuthError(err error) error {
+	if !errors.Is(err, errNotAuthenticated) {
+		return err
+	}
+	statusf("❌ %s\n", color.RedString("Not authenticated"))
+	statusf("Please run: %s\n", color.CyanString("leanmcp-cli auth login --api-key <your-key>"))
+	return nil
 }
 
 // handleAPIError provides user-friendly error messages for common API errors
 func handleAPIError(err error, action string) {
 	if strings.Contains(err.Error(), "status 401") {
-		fmt.Printf("❌ %s\n", color.RedString("Authentication failed"))
-		fmt.Printf("Your API key is invalid or has expired.\n")
-		fmt.Printf("Please run: %s\n", color.CyanString("leanmcp-cli auth login --api-key <your-key>"))
+		statusf("❌ %s\n", color.RedString("Authentication failed"))
+		statusf("Your API key is invalid or has expired.\n")
+		statusf("Please run: %s\n", color.CyanString("leanmcp-cli auth login --api-key <your-key>"))
 		return
 	}
 	if strings.Contains(err.Error(), "status 403") {
-		fmt.Printf("❌ %s\n", color.RedString("Access denied"))
-		fmt.Printf("Your API key doesn't have permission to %s.\n", action)
+		statusf("❌ %s\n", color.RedString("Access denied"))
+		statusf("Your API key doesn't have permission to %s.\n", action)
 		return
 	}
 	if strings.Contains(err.Error(), "status 404") {
-		fmt.Printf("❌ %s\n", color.RedString("Not found"))
-		fmt.Printf("The requested resource was not found.\n")
+		statusf("❌ %s\n", color.RedString("Not found"))
+		statusf("The requested resource was not found.\n")
 		return
 	}
 	if strings.Contains(err.Error(), "connection failed") {
-		fmt.Printf("❌ %s\n", color.RedString("Connection failed"))
-		fmt.Printf("Unable to connect to the API. Please check your internet connection.\n")
+		statusf("❌ %s\n", color.RedString("Connection failed"))
+		statusf("Unable to connect to the API. Please check your internet connection.\n")
 		return
 	}
 	// Generic error for other cases
-	fmt.Printf("❌ %s: %v\n", color.RedString("Error"), err)
+	statusf("❌ %s: %v\n", color.RedString("Error"), err)
 }
 
 func init() {
@@ -323,6 +624,12 @@ func init() {
 	projectsCreateCmd.Flags().StringP("name", "n", "", "Project name")
 	projectsCreateCmd.Flags().StringP("description", "d", "", "Project description")
 	projectsCreateCmd.Flags().StringP("path", "p", "", "Path to project directory (defaults to current directory)")
+	projectsCreateCmd.Flags().StringSlice("include", []string{}, "Patterns to upload even if ignored (e.g. dist)")
+	projectsCreateCmd.Flags().StringSlice("exclude", []string{}, "Additional patterns to leave out of the upload")
+	projectsCreateCmd.Flags().String("symlinks", "", "How to package symlinks: preserve, follow or skip (default preserve)")
+	projectsCreateCmd.Flags().String("archive-format", "", "Archive format: zip, tar.gz or tar.zst (default: preferred by the backend)")
+	projectsCreateCmd.Flags().Int("compression-level", 0, "Compression level, 1-9 for zip and tar.gz or 1-22 for tar.zst (default: format default)")
+	projectsCreateCmd.Flags().Bool("allow-secrets", false, "Upload even if files appear to contain secrets")
 	// Note: name is no longer required - interactive mode will prompt if missing
 
 	// Delete command flags
@@ -10,8 +10,11 @@ import (
 )
 
 var (
-	cfgFile string
-	verbose bool
+	cfgFile     string
+	verbose     bool
+	apiURL      string
+	environment string
+	profileName string
 	
 	// Version information
 	Version = "1.1.0"
@@ -25,11 +28,22 @@ var rootCmd = &cobra.Command{
 chats, deployments, and API keys.
 
 LeanMCP CLI provides a simple way to interact with LeanMCP services from the command line.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Initialize configuration
 		if err := config.Initialize(cfgFile); err != nil && verbose {
 			fmt.Printf("Warning: Could not initialize config: %v\n", err)
 		}
+
+		// Select the profile for this run
+		profile := profileName
+		if profile == "" {
+			profile = os.Getenv("LEANMCP_PROFILE")
+		}
+		if err := config.UseProfile(profile); err != nil {
+			return err
+		}
+
+		return initPrinter()
 	},
 }
 
@@ -48,6 +62,19 @@ func init() {
 		"config file (default is $HOME/.leanmcp-cli/config.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false,
 		"verbose output")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "",
+		"output format: table, json, yaml, csv, template=<go-template> or jsonpath=<expr> (default table)")
+	rootCmd.PersistentFlags().StringVar(&apiURL, "api-url", "",
+		"API base URL, overrides --env and base_url (env: LEANMCP_API_URL)")
+	rootCmd.PersistentFlags().StringVar(&environment, "env", "",
+		"named API environment: prod, staging, local or one from your config (env: LEANMCP_ENV)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "",
+		"credentials profile to use instead of the current one (env: LEANMCP_PROFILE)")
+
+	viper.BindPFlag("api_url", rootCmd.PersistentFlags().Lookup("api-url"))
+	viper.BindPFlag("environment", rootCmd.PersistentFlags().Lookup("env"))
+	viper.BindEnv("api_url", "LEANMCP_API_URL")
+	viper.BindEnv("environment", "LEANMCP_ENV")
 
 	// Add alias command
 	rootCmd.AddCommand(&cobra.Command{
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ddod/leanmcp-cli/internal/filesystem"
+	"github.com/mattn/go-isatty"
+)
+
+// uploadProgress renders upload progress: a redrawn progress bar on a
+// terminal, or a plain line every 10% when output is redirected
+type uploadProgress struct {
+	out         io.Writer
+	interactive bool
+
+	started    time.Time
+	baseline   int64 // bytes already uploaded before this run (resumed parts)
+	lastRender time.Time
+	lastStep   int64
+	rendered   bool
+}
+
+// newUploadProgress creates a renderer writing to the status output
+func newUploadProgress() *uploadProgress {
+	out := statusOut()
+	interactive := false
+	if f, ok := out.(*os.File); ok {
+		interactive = isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+	}
+
+	return &uploadProgress{
+		out:         out,
+		interactive: interactive,
+		baseline:    -1,
+		lastStep:    -1,
+	}
+}
+
+// Update reports sent of total bytes uploaded
+func (p *uploadProgress) Update(sent, total int64) {
+	now := time.Now()
+	if p.baseline < 0 {
+		p.started = now
+		p.baseline = sent
+	}
+
+	// Nothing sent yet in this run
+	if total <= 0 || (sent == p.baseline && sent < total) {
+		return
+	}
+	percent := float64(sent) * 100 / float64(total)
+
+	if p.interactive {
+		// Redraw at most ten times a second, but always show completion
+		if sent < total && now.Sub(p.lastRender) < 100*time.Millisecond {
+			return
+		}
+		p.lastRender = now
+		p.rendered = true
+		fmt.Fprintf(p.out, "\rUploading %s %5.1f%% %s/%s %s%s\033[K",
+			createProgressBar(int(percent)),
+			percent,
+			filesystem.GetHumanReadableSize(sent),
+			filesystem.GetHumanReadableSize(total),
+			p.throughput(sent, now),
+			p.eta(sent, total, now))
+		return
+	}
+
+	step := int64(percent) / 10
+	if step <= p.lastStep {
+		return
+	}
+	p.lastStep = step
+	p.rendered = true
+	fmt.Fprintf(p.out, "Uploaded %s of %s (%.0f%%), %s%s\n",
+		filesystem.GetHumanReadableSize(sent),
+		filesystem.GetHumanReadableSize(total),
+		percent,
+		p.throughput(sent, now),
+		p.eta(sent, total, now))
+}
+
+// Finish ends the progress line
+func (p *uploadProgress) Finish() {
+	if p.interactive && p.rendered {
+		fmt.Fprintln(p.out)
+	}
+}
+
+// rate returns the bytes per second sent during this run
+func (p *uploadProgress) rate(sent int64, now time.Time) float64 {
+	elapsed := now.Sub(p.started).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(sent-p.baseline) / elapsed
+}
+
+// throughput formats the upload rate, e.g. "4.2 MB/s"
+func (p *uploadProgress) throughput(sent int64, now time.Time) string {
+	return filesystem.GetHumanReadableSize(int64(p.rate(sent, now))) + "/s"
+}
+
+// eta formats the estimated time remaining, or nothing if unknown
+func (p *uploadProgress) eta(sent, total int64, now time.Time) string {
+	rate := p.rate(sent, now)
+	if sent >= total || rate <= 0 {
+		return ""
+	}
+	remaining := time.Duration(float64(total-sent) / rate * float64(time.Second))
+	return " - ETA: " + remaining.Round(time.Second).String()
+}
@@ -10,11 +10,18 @@ var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
 	Long:  "Display version information",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("leanmcp version %s\n", Version)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return printer.Print(&versionOutput{Version: Version}, func() {
+			fmt.Printf("leanmcp version %s\n", Version)
+		})
 	},
 }
 
+// versionOutput is the machine-readable form of 'version'
+type versionOutput struct {
+	Version string `json:"version"`
+}
+
 func init() {
 	rootCmd.AddCommand(versionCmd)
 }
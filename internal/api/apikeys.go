@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ListAPIKeys gets all API keys of the authenticated account
+func (c *Client) ListAPIKeys() ([]APIKeyInfo, error) {
+	resp, err := c.makeRequest("GET", "/api/api-keys", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("list API keys failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var keys []APIKeyInfo
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// CreateAPIKey creates a new API key. The secret is only returned here.
+func (c *Client) CreateAPIKey(req CreateAPIKeyRequest) (*CreatedAPIKey, error) {
+	resp, err := c.makeRequest("POST", "/api/api-keys", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("create API key failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var key CreatedAPIKey
+	if err := json.NewDecoder(resp.Body).Decode(&key); err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// RevokeAPIKey revokes an API key so it can no longer be used
+func (c *Client) RevokeAPIKey(keyID string) error {
+	resp, err := c.makeRequest("DELETE", fmt.Sprintf("/api/api-keys/%s", keyID), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("API key not found")
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("revoke API key failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// apiKeyServer serves one response and records the request it got
+type apiKeyServer struct {
+	status int
+	body   string
+
+	method, path, auth string
+	request            map[string]interface{}
+}
+
+func (s *apiKeyServer) start(t *testing.T) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.method, s.path, s.auth = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		if r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&s.request); err != nil {
+				t.Errorf("invalid request body: %v", err)
+			}
+		}
+		w.WriteHeader(s.status)
+		fmt.Fprint(w, s.body)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient("airtrain_test", server.URL)
+}
+
+func TestListAPIKeys(t *testing.T) {
+	expires := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	server := &apiKeyServer{status: http.StatusOK, body: `[
+		{"id": "k1", "name": "ci", "scopes": ["BUILD_AND_DEPLOY"], "isActive": true, "createdAt": "2025-01-01T00:00:00Z", "expiresAt": "2026-03-01T00:00:00Z"},
+		{"id": "k2", "name": "old", "scopes": [], "isActive": false, "createdAt": "2024-06-01T00:00:00Z"}
+	]`}
+	client := server.start(t)
+
+	keys, err := client.ListAPIKeys()
+	if err != nil {
+		t.Fatalf("ListAPIKeys() error = %v", err)
+	}
+
+	want := []APIKeyInfo{
+		{ID: "k1", Name: "ci", Scopes: []string{"BUILD_AND_DEPLOY"}, IsActive: true,
+			CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), ExpiresAt: &expires},
+		{ID: "k2", Name: "old", Scopes: []string{}, CreatedAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("ListAPIKeys() = %+v, want %+v", keys, want)
+	}
+	if server.method != "GET" || server.path != "/api/api-keys" || server.auth != "Bearer airtrain_test" {
+		t.Errorf("request = %s %s (Authorization %q)", server.method, server.path, server.auth)
+	}
+}
+
+func TestListAPIKeysError(t *testing.T) {
+	client := (&apiKeyServer{status: http.StatusForbidden, body: "missing scope"}).start(t)
+
+	_, err := client.ListAPIKeys()
+	if err == nil || !strings.Contains(err.Error(), "status 403") || !strings.Contains(err.Error(), "missing scope") {
+		t.Errorf("ListAPIKeys() error = %v, want the status and body", err)
+	}
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	expires := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"created", http.StatusCreated, false},
+		{"ok", http.StatusOK, false},
+		{"bad request", http.StatusBadRequest, true},
+		{"unauthorized", http.StatusUnauthorized, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &apiKeyServer{status: tt.status, body: `{"id": "k3", "name": "deploy", "scopes": ["BUILD_AND_DEPLOY"], "isActive": true, "createdAt": "2025-01-01T00:00:00Z", "key": "airtrain_new"}`}
+			client := server.start(t)
+
+			key, err := client.CreateAPIKey(CreateAPIKeyRequest{Name: "deploy", Scopes: []string{"BUILD_AND_DEPLOY"}, ExpiresAt: &expires})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateAPIKey() error = %v, want error: %v", err, tt.wantErr)
+			}
+			if server.method != "POST" || server.path != "/api/api-keys" {
+				t.Errorf("request = %s %s", server.method, server.path)
+			}
+			wantRequest := map[string]interface{}{"name": "deploy", "scopes": []interface{}{"BUILD_AND_DEPLOY"}, "expiresAt": "2026-03-01T00:00:00Z"}
+			if !reflect.DeepEqual(server.request, wantRequest) {
+				t.Errorf("request body = %v, want %v", server.request, wantRequest)
+			}
+			if err != nil {
+				return
+			}
+			if key.ID != "k3" || key.Key != "airtrain_new" || key.Name != "deploy" {
+				t.Errorf("CreateAPIKey() = %+v", key)
+			}
+		})
+	}
+}
+
+func TestCreateAPIKeyWithoutExpiry(t *testing.T) {
+	server := &apiKeyServer{status: http.StatusCreated, body: `{"id": "k4", "key": "airtrain_new"}`}
+	client := server.start(t)
+
+	if _, err := client.CreateAPIKey(CreateAPIKeyRequest{Name: "forever", Scopes: []string{}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := server.request["expiresAt"]; ok {
+		t.Errorf("request body %v sends expiresAt for a key that never expires", server.request)
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{"ok", http.StatusOK, ""},
+		{"no content", http.StatusNoContent, ""},
+		{"not found", http.StatusNotFound, "API key not found"},
+		{"server error", http.StatusInternalServerError, "status 500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &apiKeyServer{status: tt.status}
+			client := server.start(t)
+
+			err := client.RevokeAPIKey("k1")
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("RevokeAPIKey() error = %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("RevokeAPIKey() error = %v, want %q", err, tt.wantErr)
+			}
+			if server.method != "DELETE" || server.path != "/api/api-keys/k1" {
+				t.Errorf("request = %s %s", server.method, server.path)
+			}
+		})
+	}
+}
@@ -1,16 +1,21 @@
 package api
 
 import (
-	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/ddod/leanmcp-cli/internal/sse"
 )
 
+// DefaultBaseURL is the production API endpoint, used when no base URL is given
+const DefaultBaseURL = "https://join-us.cracked-devs.link"
+
 // Client represents the API client
 type Client struct {
 	apiKey     string
@@ -18,10 +23,13 @@ type Client struct {
 	httpClient *http.Client
 }
 
-// NewClient creates a new API client
-func NewClient(apiKey string) *Client {
-	// Hardcoded base URL - never changes
-	baseURL := "https://join-us.cracked-devs.link"
+// NewClient creates a new API client for the given base URL.
+// An empty baseURL falls back to DefaultBaseURL.
+func NewClient(apiKey, baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
 
 	return &Client{
 		apiKey:  apiKey,
@@ -32,8 +40,18 @@ func NewClient(apiKey string) *Client {
 	}
 }
 
+// BaseURL returns the API base URL the client sends requests to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
 // makeRequest makes an HTTP request with authentication
 func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.Response, error) {
+	return c.makeRequestContext(context.Background(), method, endpoint, body)
+}
+
+// makeRequestContext makes an authenticated HTTP request bound to ctx
+func (c *Client) makeRequestContext(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
 	var reqBody io.Reader
 	if body != nil {
 		jsonData, err := json.Marshal(body)
@@ -43,7 +61,7 @@ func (c *Client) makeRequest(method, endpoint string, body interface{}) (*http.R
 		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequest(method, c.baseURL+endpoint, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, reqBody)
 	if err != nil {
 		return nil, err
 	}
@@ -91,88 +109,160 @@ func (c *Client) GetAPIKeyInfo() (*APIKeyInfo, error) {
 	return &apiKeyInfo, nil
 }
 
-// DeployAndStream starts an end-to-end deployment with streaming progress updates
+// deployStatusPollInterval is how often a deployment is checked after its
+// stream dropped
+var deployStatusPollInterval = 3 * time.Second
+
+// deployStatusMaxErrors is how many consecutive failed status checks are
+// tolerated before the deployment is reported as lost
+const deployStatusMaxErrors = 5
+
+// DeployAndStream starts an end-to-end deployment with streaming progress updates.
+// The stream cannot be reopened, so if the connection drops mid-deployment the
+// deployment is followed through GET /api/deployments/{id} instead of failing
+// the deploy.
 func (c *Client) DeployAndStream(request *DeployStreamRequest, updateHandler func(*StreamUpdate) error) error {
-	// Create HTTP request for streaming endpoint
 	jsonData, err := json.Marshal(request)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", c.baseURL+"/api-key/end-to-end/deploy-stream", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Set headers for streaming
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Cache-Control", "no-cache")
-
 	// Create client with no timeout for streaming
 	streamClient := &http.Client{
 		Timeout: 0, // No timeout for streaming
 	}
 
-	resp, err := streamClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to start deployment stream: %w", err)
-	}
-	defer resp.Body.Close()
+	var (
+		deploymentID string
+		handlerErr   error
+	)
 
-	// Check for success status codes (200 OK or 201 Created)
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("deployment failed (status %d): %s", resp.StatusCode, string(body))
+	stream := &sse.Stream{
+		// Re-posting could start a second deployment, so never reconnect
+		MaxRetries: 0,
+		Connect: func(ctx context.Context, lastEventID string) (*http.Response, error) {
+			req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api-key/end-to-end/deploy-stream", bytes.NewBuffer(jsonData))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create request: %w", err)
+			}
+
+			// Set headers for streaming
+			req.Header.Set("Authorization", "Bearer "+c.apiKey)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept", "text/event-stream")
+			req.Header.Set("Cache-Control", "no-cache")
+
+			resp, err := streamClient.Do(req)
+			if err != nil {
+				return nil, sse.Permanent(fmt.Errorf("failed to start deployment stream: %w", err))
+			}
+
+			// The request is not safe to repeat, so any failure is final
+			if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+				defer resp.Body.Close()
+				body, _ := io.ReadAll(resp.Body)
+				return nil, sse.Permanent(fmt.Errorf("deployment failed (status %d): %s", resp.StatusCode, string(body)))
+			}
+			return resp, nil
+		},
 	}
 
-	// Process Server-Sent Events stream
-	scanner := bufio.NewScanner(resp.Body)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+	err = stream.Run(context.Background(), func(event *sse.Event) error {
+		data := strings.TrimSpace(event.Data)
 
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, ":") {
-			continue
+		// Skip empty data; the end-of-stream marker finishes the stream
+		switch data {
+		case "":
+			return nil
+		case "[DONE]":
+			return sse.ErrStop
 		}
 
-		// Parse Server-Sent Events format
-		if strings.HasPrefix(line, "data: ") {
-			data := strings.TrimPrefix(line, "data: ")
-
-			// Skip empty data lines
-			if data == "" || data == "[DONE]" {
-				continue
+		// Parse JSON data
+		var update StreamUpdate
+		if err := json.Unmarshal([]byte(data), &update); err != nil {
+			// If JSON parsing fails, treat as a simple message
+			update = StreamUpdate{
+				Type:    "log",
+				Message: data,
 			}
+		}
 
-			// Parse JSON data
-			var update StreamUpdate
-			if err := json.Unmarshal([]byte(data), &update); err != nil {
-				// If JSON parsing fails, treat as a simple message
-				update = StreamUpdate{
-					Type:    "log",
-					Message: data,
-				}
-			}
+		// Fall back to the SSE event name when the payload carries no type
+		if update.Type == "" && event.Type != "message" {
+			update.Type = event.Type
+		}
+
+		if update.DeploymentID != "" {
+			deploymentID = update.DeploymentID
+		}
+
+		// Call the update handler
+		if err := updateHandler(&update); err != nil {
+			handlerErr = err
+			return err
+		}
+
+		// Stop processing if deployment is complete or failed
+		if update.Type == "complete" || update.Type == "error" ||
+			update.CurrentStep == "COMPLETED" || update.CurrentStep == "FAILED" ||
+			update.BuildStatus == "failed" {
+			return sse.ErrStop
+		}
+
+		return nil
+	})
+	switch {
+	case err == nil:
+		return nil
+	case handlerErr != nil:
+		return handlerErr
+	case deploymentID == "":
+		return fmt.Errorf("deployment stream failed before the deployment ID was received: %w", err)
+	}
+
+	_ = updateHandler(&StreamUpdate{
+		Type:    "log",
+		Message: fmt.Sprintf("\nConnection lost (%v), following deployment %s...", err, deploymentID),
+	})
+	return c.waitForDeployment(deploymentID, updateHandler)
+}
+
+// waitForDeployment polls a deployment until it succeeds or fails, reporting
+// status changes and the final result as stream updates
+func (c *Client) waitForDeployment(deploymentID string, updateHandler func(*StreamUpdate) error) error {
+	var lastStatus string
+	failures := 0
 
-			// Call the update handler
-			if err := updateHandler(&update); err != nil {
-				return err
+	for {
+		deployment, err := c.GetDeployment(deploymentID)
+		if err != nil {
+			failures++
+			if failures >= deployStatusMaxErrors {
+				return fmt.Errorf("lost track of deployment %s: %w", deploymentID, err)
 			}
+		} else {
+			failures = 0
 
-			// Stop processing if deployment is complete or failed
-			if update.Type == "complete" || update.Type == "error" ||
-				update.CurrentStep == "COMPLETED" || update.CurrentStep == "FAILED" ||
-				update.BuildStatus == "failed" {
-				break
+			var update *StreamUpdate
+			switch strings.ToLower(deployment.Status) {
+			case "active", "running", "live", "deployed", "completed", "success":
+				update = &StreamUpdate{Type: "complete", DeploymentID: deploymentID, CurrentStep: "COMPLETED", DeploymentURL: deployment.URL}
+			case "failed", "error", "stopped":
+				update = &StreamUpdate{Type: "error", DeploymentID: deploymentID, CurrentStep: "FAILED", Error: "deployment " + deployment.Status}
+			default:
+				if deployment.Status != lastStatus {
+					lastStatus = deployment.Status
+					if err := updateHandler(&StreamUpdate{Type: "log", DeploymentID: deploymentID, Message: "Deployment status: " + deployment.Status}); err != nil {
+						return err
+					}
+				}
+			}
+			if update != nil {
+				return updateHandler(update)
 			}
 		}
-	}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("error reading stream: %w", err)
+		time.Sleep(deployStatusPollInterval)
 	}
-
-	return nil
 }
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDeployAndStream(t *testing.T) {
+	defer func(interval time.Duration) { deployStatusPollInterval = interval }(deployStatusPollInterval)
+	deployStatusPollInterval = time.Millisecond
+
+	errFailed := errors.New("deployment failed")
+
+	tests := []struct {
+		name     string
+		stream   string
+		statuses []string // returned by GET /api/deployments/dep-1 in turn
+		wantErr  bool
+		wantLast string // type and step of the last update
+		wantURL  string
+		wantReqs []string
+	}{
+		{
+			name:     "terminal event then EOF",
+			stream:   "data: {\"type\":\"progress\",\"deploymentId\":\"dep-1\",\"currentStep\":\"BUILDING\"}\n\ndata: {\"type\":\"complete\",\"currentStep\":\"COMPLETED\",\"deploymentUrl\":\"https://x.test\"}\n\n",
+			wantLast: "complete/COMPLETED",
+			wantURL:  "https://x.test",
+			wantReqs: []string{"POST /api-key/end-to-end/deploy-stream"},
+		},
+		{
+			name:     "done marker then EOF",
+			stream:   "data: {\"type\":\"log\",\"message\":\"hello\"}\n\ndata: [DONE]\n\n",
+			wantLast: "log/",
+			wantReqs: []string{"POST /api-key/end-to-end/deploy-stream"},
+		},
+		{
+			name:     "dropped after the deployment ID",
+			stream:   "data: {\"type\":\"progress\",\"deploymentId\":\"dep-1\",\"currentStep\":\"BUILDING\"}\n\n",
+			statuses: []string{"building", "deploying", "deploying", "active"},
+			wantLast: "complete/COMPLETED",
+			wantURL:  "https://dep-1.test",
+			wantReqs: []string{
+				"POST /api-key/end-to-end/deploy-stream",
+				"GET /api/deployments/dep-1",
+				"GET /api/deployments/dep-1",
+				"GET /api/deployments/dep-1",
+				"GET /api/deployments/dep-1",
+			},
+		},
+		{
+			name:     "dropped and the deployment failed",
+			stream:   "data: {\"deploymentId\":\"dep-1\",\"currentStep\":\"BUILDING\"}\n\n",
+			statuses: []string{"failed"},
+			wantErr:  true,
+			wantLast: "error/FAILED",
+			wantReqs: []string{"POST /api-key/end-to-end/deploy-stream", "GET /api/deployments/dep-1"},
+		},
+		{
+			name:     "dropped before the deployment ID",
+			stream:   "data: {\"type\":\"log\",\"message\":\"queued\"}\n\n",
+			wantErr:  true,
+			wantLast: "log/",
+			wantReqs: []string{"POST /api-key/end-to-end/deploy-stream"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var requests []string
+			polls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				mu.Unlock()
+
+				switch {
+				case r.Method == "POST" && r.URL.Path == "/api-key/end-to-end/deploy-stream":
+					w.Header().Set("Content-Type", "text/event-stream")
+					fmt.Fprint(w, tt.stream)
+				case r.Method == "GET" && r.URL.Path == "/api/deployments/dep-1" && polls < len(tt.statuses):
+					fmt.Fprintf(w, `{"id":"dep-1","status":%q,"url":"https://dep-1.test"}`, tt.statuses[polls])
+					polls++
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("airtrain_test", server.URL)
+			var last *StreamUpdate
+			err := client.DeployAndStream(&DeployStreamRequest{ProjectID: "p1"}, func(update *StreamUpdate) error {
+				last = update
+				if update.Type == "error" {
+					return errFailed
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeployAndStream() error = %v, want error: %v", err, tt.wantErr)
+			}
+
+			if last == nil {
+				t.Fatal("no updates delivered")
+			}
+			if got := last.Type + "/" + last.CurrentStep; got != tt.wantLast {
+				t.Errorf("last update = %s, want %s", got, tt.wantLast)
+			}
+			if last.DeploymentURL != tt.wantURL {
+				t.Errorf("deployment URL = %q, want %q", last.DeploymentURL, tt.wantURL)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if got, want := strings.Join(requests, ", "), strings.Join(tt.wantReqs, ", "); got != want {
+				t.Errorf("requests = %s, want %s", got, want)
+			}
+		})
+	}
+}
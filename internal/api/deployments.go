@@ -0,0 +1,211 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ListDeployments gets all deployments, optionally filtered by project
+func (c *Client) ListDeployments(projectID string) ([]Deployment, error) {
+	endpoint := "/api/deployments"
+	if projectID != "" {
+		endpoint += "?projectId=" + url.QueryEscape(projectID)
+	}
+
+	resp, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("list deployments failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var deployments []Deployment
+	if err := json.NewDecoder(resp.Body).Decode(&deployments); err != nil {
+		return nil, err
+	}
+
+	return deployments, nil
+}
+
+// GetDeployment gets a specific deployment by ID
+func (c *Client) GetDeployment(deploymentID string) (*Deployment, error) {
+	resp, err := c.makeRequest("GET", fmt.Sprintf("/api/deployments/%s", deploymentID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("deployment not found")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("get deployment failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var deployment Deployment
+	if err := json.NewDecoder(resp.Body).Decode(&deployment); err != nil {
+		return nil, err
+	}
+
+	return &deployment, nil
+}
+
+// GetDeploymentLogs gets the runtime logs for a deployment
+func (c *Client) GetDeploymentLogs(deploymentID string, opts DeploymentLogsOptions) (*DeploymentLogsResponse, error) {
+	logs, _, err := c.fetchDeploymentLogs(context.Background(), deploymentID, opts)
+	return logs, err
+}
+
+// FollowDeploymentLogs streams new log lines for a deployment to handler until
+// ctx is cancelled or handler returns an error. It long-polls the logs
+// endpoint and reconnects with exponential backoff when the connection drops
+// or the server is temporarily unavailable.
+func (c *Client) FollowDeploymentLogs(ctx context.Context, deploymentID string, opts DeploymentLogsOptions, handler func(DeploymentLogEntry) error) error {
+	if opts.Wait == 0 {
+		opts.Wait = followPollWait
+	}
+
+	backoff := followMinBackoff
+	var lastSeen time.Time
+	var atLastSeen int // lines delivered with the timestamp lastSeen
+
+	for {
+		logs, retry, err := c.fetchDeploymentLogs(ctx, deploymentID, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if !retry {
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > followMaxBackoff {
+				backoff = followMaxBackoff
+			}
+			continue
+		}
+		backoff = followMinBackoff
+
+		// Without a server cursor we resume by timestamp. The server returns
+		// lines at or after lastSeen, so skip older lines and as many lines
+		// at lastSeen as were already delivered; later lines that share the
+		// timestamp are new.
+		bySince := opts.Cursor == "" && !lastSeen.IsZero()
+		skipAtLastSeen := atLastSeen
+		delivered := 0
+		for _, entry := range logs.Logs {
+			if bySince {
+				if entry.Timestamp.Before(lastSeen) {
+					continue
+				}
+				if entry.Timestamp.Equal(lastSeen) && skipAtLastSeen > 0 {
+					skipAtLastSeen--
+					continue
+				}
+			}
+			if err := handler(entry); err != nil {
+				return err
+			}
+			delivered++
+			switch {
+			case entry.Timestamp.After(lastSeen):
+				lastSeen = entry.Timestamp
+				atLastSeen = 1
+			case entry.Timestamp.Equal(lastSeen):
+				atLastSeen++
+			}
+		}
+
+		// Only the first request honours --tail; afterwards we want everything new
+		opts.Tail = 0
+		if logs.NextCursor != "" {
+			opts.Cursor = logs.NextCursor
+		} else if !lastSeen.IsZero() {
+			opts.Since = lastSeen
+		}
+
+		if delivered == 0 {
+			// The server returned early without holding the request open,
+			// or only repeated lines at lastSeen; avoid hammering it
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(followIdleDelay):
+			}
+		}
+	}
+}
+
+const (
+	followPollWait   = 20 * time.Second
+	followMinBackoff = 1 * time.Second
+	followMaxBackoff = 30 * time.Second
+)
+
+// followIdleDelay is how long to wait before polling again after a response
+// with no new lines
+var followIdleDelay = 2 * time.Second
+
+// fetchDeploymentLogs performs a single logs request. The returned bool reports
+// whether a failed request is worth retrying (network errors and 5xx responses).
+func (c *Client) fetchDeploymentLogs(ctx context.Context, deploymentID string, opts DeploymentLogsOptions) (*DeploymentLogsResponse, bool, error) {
+	query := url.Values{}
+	if !opts.Since.IsZero() {
+		query.Set("since", opts.Since.UTC().Format(time.RFC3339Nano))
+	}
+	if opts.Tail > 0 {
+		query.Set("tail", strconv.Itoa(opts.Tail))
+	}
+	if opts.Cursor != "" {
+		query.Set("cursor", opts.Cursor)
+	}
+	if opts.Wait > 0 {
+		query.Set("wait", strconv.Itoa(int(opts.Wait.Seconds())))
+	}
+
+	endpoint := fmt.Sprintf("/api/deployments/%s/logs", deploymentID)
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	resp, err := c.makeRequestContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, true, fmt.Errorf("connection failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, fmt.Errorf("deployment not found")
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, resp.StatusCode >= 500, fmt.Errorf("get deployment logs failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var logs DeploymentLogsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&logs); err != nil {
+		// A body cut off mid-stream is a dropped connection, not a bad response
+		return nil, true, fmt.Errorf("failed to read deployment logs: %w", err)
+	}
+
+	return &logs, false, nil
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFollowDeploymentLogsBoundaryTimestamps(t *testing.T) {
+	t1 := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Second)
+	t3 := t2.Add(time.Second)
+
+	// Each poll returns the lines at or after "since", including lines that
+	// share the boundary timestamp and arrived after the previous poll
+	polls := [][]DeploymentLogEntry{
+		{{Timestamp: t1, Message: "a"}, {Timestamp: t2, Message: "b"}},
+		{{Timestamp: t2, Message: "b"}, {Timestamp: t2, Message: "c"}, {Timestamp: t3, Message: "d"}},
+		{{Timestamp: t3, Message: "d"}, {Timestamp: t3, Message: "d"}, {Timestamp: t3, Message: "e"}},
+	}
+	wantSince := []string{"", t2.Format(time.RFC3339Nano), t3.Format(time.RFC3339Nano)}
+
+	var mu sync.Mutex
+	var sinces []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		poll := len(sinces)
+		sinces = append(sinces, r.URL.Query().Get("since"))
+		mu.Unlock()
+
+		response := DeploymentLogsResponse{DeploymentID: "dep"}
+		if poll < len(polls) {
+			response.Logs = polls[poll]
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	// Lines that are wrongly skipped would leave the follow loop polling
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := NewClient("airtrain_test", server.URL)
+	errDone := errors.New("done")
+	var got []string
+	err := client.FollowDeploymentLogs(ctx, "dep", DeploymentLogsOptions{Wait: time.Second}, func(entry DeploymentLogEntry) error {
+		got = append(got, entry.Message)
+		if len(got) == 6 {
+			return errDone
+		}
+		return nil
+	})
+	if !errors.Is(err, errDone) {
+		t.Fatalf("FollowDeploymentLogs() error = %v", err)
+	}
+
+	if want := []string{"a", "b", "c", "d", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(sinces, wantSince) {
+		t.Errorf("requested since %v, want %v", sinces, wantSince)
+	}
+}
+
+func TestFollowDeploymentLogsIdleBoundaryLine(t *testing.T) {
+	defer func(delay time.Duration) { followIdleDelay = delay }(followIdleDelay)
+	followIdleDelay = 50 * time.Millisecond
+
+	// The server answers right away and, since "since" is inclusive, keeps
+	// returning the line at the boundary
+	line := DeploymentLogEntry{Timestamp: time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC), Message: "a"}
+	var mu sync.Mutex
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		polls++
+		mu.Unlock()
+		json.NewEncoder(w).Encode(DeploymentLogsResponse{DeploymentID: "dep", Logs: []DeploymentLogEntry{line}})
+	}))
+	defer server.Close()
+
+	const duration = 500 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	client := NewClient("airtrain_test", server.URL)
+	var got []string
+	err := client.FollowDeploymentLogs(ctx, "dep", DeploymentLogsOptions{Wait: time.Second}, func(entry DeploymentLogEntry) error {
+		got = append(got, entry.Message)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("FollowDeploymentLogs() error = %v", err)
+	}
+
+	if want := []string{"a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if limit := int(duration/followIdleDelay) + 2; polls > limit {
+		t.Errorf("polled %d times in %v, want at most %d", polls, duration, limit)
+	}
+}
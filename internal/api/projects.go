@@ -1,7 +1,7 @@
 package api
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -78,6 +78,19 @@ func (c *Client) CreateProject(req CreateProjectRequest) (*Project, error) {
 	return &project, nil
 }
 
+// SetFramework fills in the framework fields from a detected framework, which
+// may be nil
+func (r *CreateProjectRequest) SetFramework(framework *filesystem.FrameworkInfo) {
+	if framework == nil {
+		return
+	}
+	r.Framework = framework.Framework
+	r.Language = framework.Language
+	r.Runtime = framework.Runtime
+	r.RuntimeVersion = framework.RuntimeVersion
+	r.Entrypoint = framework.Entrypoint
+}
+
 // DeleteProject deletes a project
 func (c *Client) DeleteProject(projectID string) error {
 	resp, err := c.makeRequest("DELETE", fmt.Sprintf("/api/projects/%s", projectID), nil)
@@ -140,15 +153,16 @@ func (c *Client) StartBuild(projectID string) (*Build, error) {
 	return &build, nil
 }
 
-// GetUploadURL gets a pre-signed URL for uploading project files
-func (c *Client) GetUploadURL(projectID, fileName string, fileSize int64) (*UploadURLResponse, error) {
+// GetUploadURL gets a pre-signed URL for uploading project files. fileType is
+// the MIME type of the archive, e.g. "application/zip".
+func (c *Client) GetUploadURL(ctx context.Context, projectID, fileName, fileType string, fileSize int64) (*UploadURLResponse, error) {
 	req := UploadURLRequest{
 		FileName: fileName,
-		FileType: "application/zip",
+		FileType: fileType,
 		FileSize: fileSize,
 	}
 	
-	resp, err := c.makeRequest("POST", fmt.Sprintf("/api/projects/%s/upload-url", projectID), req)
+	resp, err := c.makeRequestContext(ctx, "POST", fmt.Sprintf("/api/projects/%s/upload-url", projectID), req)
 	if err != nil {
 		return nil, err
 	}
@@ -167,15 +181,16 @@ func (c *Client) GetUploadURL(projectID, fileName string, fileSize int64) (*Uplo
 	return &uploadResp, nil
 }
 
-// UploadToS3 uploads data to S3 using a pre-signed URL
-func (c *Client) UploadToS3(presignedURL string, data []byte) error {
-	req, err := http.NewRequest("PUT", presignedURL, bytes.NewReader(data))
+// UploadToS3 streams size bytes from body to S3 using a pre-signed URL. The
+// content type must match the one the URL was requested for.
+func (c *Client) UploadToS3(ctx context.Context, presignedURL, contentType string, body io.Reader, size int64) error {
+	req, err := http.NewRequestWithContext(ctx, "PUT", presignedURL, body)
 	if err != nil {
 		return fmt.Errorf("failed to create upload request: %w", err)
 	}
 	
-	req.Header.Set("Content-Type", "application/zip")
-	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Type", contentType)
+	req.ContentLength = size
 	
 	client := &http.Client{
 		Timeout: 10 * time.Minute, // Allow up to 10 minutes for large uploads
@@ -220,13 +235,16 @@ func (c *Client) UpdateS3Location(projectID, s3Location string) (*Project, error
 	return &project, nil
 }
 
-// CreateProjectWithUpload creates a project and uploads files in one operation
-func (c *Client) CreateProjectWithUpload(name, description, projectPath string) (*Project, error) {
+// CreateProjectWithUpload creates a project and uploads files in one operation.
+// framework, if not nil, is sent with the project record; detect it with
+// filesystem.DetectFramework and report a detection error to the user.
+func (c *Client) CreateProjectWithUpload(name, description, projectPath string, framework *filesystem.FrameworkInfo) (*Project, error) {
 	// Step 1: Create project record
 	createReq := CreateProjectRequest{
 		Name:        name,
 		Description: description,
 	}
+	createReq.SetFramework(framework)
 	
 	project, err := c.CreateProject(createReq)
 	if err != nil {
@@ -240,20 +258,28 @@ func (c *Client) CreateProjectWithUpload(name, description, projectPath string)
 		return nil, fmt.Errorf("failed to create zip: %w", err)
 	}
 	
+	defer zipResult.Remove()
+	
 	// Step 3: Validate zip size
-	err = filesystem.ValidateZipSize(zipResult.Data)
+	err = filesystem.ValidateZipSize(zipResult.Size)
 	if err != nil {
 		return nil, fmt.Errorf("zip validation failed: %w", err)
 	}
 	
 	// Step 4: Get upload URL
-	uploadResp, err := c.GetUploadURL(project.ID, "project.zip", int64(len(zipResult.Data)))
+	uploadResp, err := c.GetUploadURL(context.Background(), project.ID, zipResult.Format.FileName(), zipResult.Format.ContentType(), zipResult.Size)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get upload URL: %w", err)
 	}
 	
 	// Step 5: Upload to S3
-	err = c.UploadToS3(uploadResp.URL, zipResult.Data)
+	zipFile, err := zipResult.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip: %w", err)
+	}
+	defer zipFile.Close()
+	
+	err = c.UploadToS3(context.Background(), uploadResp.URL, zipResult.Format.ContentType(), zipFile, zipResult.Size)
 	if err != nil {
 		return nil, fmt.Errorf("failed to upload to S3: %w", err)
 	}
@@ -12,6 +12,19 @@ type APIKeyInfo struct {
 	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
+// CreateAPIKeyRequest represents a request to create an API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"` // nil for a key that never expires
+}
+
+// CreatedAPIKey is a newly created API key, including its secret
+type CreatedAPIKey struct {
+	APIKeyInfo
+	Key string `json:"key"`
+}
+
 // Project represents a project
 type Project struct {
 	ID            string    `json:"id"`
@@ -54,12 +67,35 @@ type ChatMessage struct {
 type Deployment struct {
 	ID        string    `json:"id"`
 	ProjectID string    `json:"projectId"`
+	BuildID   string    `json:"buildId,omitempty"`
 	Status    string    `json:"status"`
 	URL       string    `json:"url,omitempty"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// DeploymentLogEntry represents a single runtime log line from a deployment
+type DeploymentLogEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level,omitempty"`
+	Message   string    `json:"message"`
+}
+
+// DeploymentLogsResponse represents runtime logs for a deployment
+type DeploymentLogsResponse struct {
+	DeploymentID string               `json:"deploymentId"`
+	Logs         []DeploymentLogEntry `json:"logs"`
+	NextCursor   string               `json:"nextCursor,omitempty"`
+}
+
+// DeploymentLogsOptions controls which deployment log lines are returned
+type DeploymentLogsOptions struct {
+	Since  time.Time     // only return lines at or after this time
+	Tail   int           // only return the last N lines (0 = all)
+	Cursor string        // resume after a previous response's NextCursor
+	Wait   time.Duration // long-poll: hold the request open until new lines arrive
+}
+
 // Build represents a project build
 type Build struct {
 	ID        string    `json:"id"`
@@ -70,10 +106,16 @@ type Build struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
-// CreateProjectRequest represents a request to create a project
+// CreateProjectRequest represents a request to create a project. The
+// framework fields are detected from the project files.
 type CreateProjectRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	Framework      string `json:"framework,omitempty"`
+	Language       string `json:"language,omitempty"`
+	Runtime        string `json:"runtime,omitempty"`
+	RuntimeVersion string `json:"runtimeVersion,omitempty"`
+	Entrypoint     string `json:"entrypoint,omitempty"`
 }
 
 // UploadURLRequest represents a request to get an upload URL
@@ -89,6 +131,96 @@ type UploadURLResponse struct {
 	S3Location string `json:"s3Location"`
 }
 
+// UploadFormatsResponse lists the archive formats the server accepts, most
+// preferred first
+type UploadFormatsResponse struct {
+	Formats []string `json:"formats"`
+}
+
+// MultipartUploadRequest represents a request to start a multipart upload
+type MultipartUploadRequest struct {
+	FileName string `json:"fileName"`
+	FileType string `json:"fileType"`
+	FileSize int64  `json:"fileSize"`
+	PartSize int64  `json:"partSize,omitempty"`
+}
+
+// MultipartUpload represents a multipart upload started on the server. The
+// server may pick a different part size than the one requested.
+type MultipartUpload struct {
+	UploadID   string `json:"uploadId"`
+	S3Location string `json:"s3Location"`
+	PartSize   int64  `json:"partSize"`
+}
+
+// PartURLsRequest represents a request for pre-signed part upload URLs
+type PartURLsRequest struct {
+	PartNumbers []int `json:"partNumbers"`
+}
+
+// PartURL is a pre-signed URL for uploading one part
+type PartURL struct {
+	PartNumber int    `json:"partNumber"`
+	URL        string `json:"url"`
+}
+
+// PartURLsResponse represents the response from a part URLs request
+type PartURLsResponse struct {
+	Parts []PartURL `json:"parts"`
+}
+
+// CompletedPart identifies an uploaded part by the ETag S3 returned for it
+type CompletedPart struct {
+	PartNumber int    `json:"partNumber"`
+	ETag       string `json:"etag"`
+}
+
+// CompleteMultipartUploadRequest represents a request to assemble the parts
+type CompleteMultipartUploadRequest struct {
+	Parts []CompletedPart `json:"parts"`
+}
+
+// CompleteMultipartUploadResponse represents the assembled upload
+type CompleteMultipartUploadResponse struct {
+	S3Location string `json:"s3Location"`
+}
+
+// ManifestFile describes one project file by content hash. Symlinks carry
+// their target, which is also the content of their blob.
+type ManifestFile struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	Mode    uint32 `json:"mode"`
+	SHA256  string `json:"sha256"`
+	Symlink string `json:"symlink,omitempty"`
+}
+
+// MissingBlobsRequest asks which content hashes the server does not have yet
+type MissingBlobsRequest struct {
+	Hashes []string `json:"hashes"`
+}
+
+// BlobUploadURL is a pre-signed URL for uploading one missing blob
+type BlobUploadURL struct {
+	Hash string `json:"hash"`
+	URL  string `json:"url"`
+}
+
+// MissingBlobsResponse lists the blobs that still have to be uploaded
+type MissingBlobsResponse struct {
+	Missing []BlobUploadURL `json:"missing"`
+}
+
+// CommitManifestRequest represents a request to assemble a project from blobs
+type CommitManifestRequest struct {
+	Files []ManifestFile `json:"files"`
+}
+
+// CommitManifestResponse represents the assembled project source
+type CommitManifestResponse struct {
+	S3Location string `json:"s3Location"`
+}
+
 // UpdateS3LocationRequest represents a request to update S3 location
 type UpdateS3LocationRequest struct {
 	S3Location string `json:"s3Location"`
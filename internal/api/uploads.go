@@ -0,0 +1,257 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrMultipartUnsupported is returned when the server has no multipart upload
+// endpoints, in which case callers should fall back to a single upload
+var ErrMultipartUnsupported = errors.New("multipart uploads are not supported by this server")
+
+// ErrIncrementalUnsupported is returned when the server cannot assemble a
+// project from a file manifest, in which case callers should upload an archive
+var ErrIncrementalUnsupported = errors.New("incremental uploads are not supported by this server")
+
+// ErrUploadFormatsUnsupported is returned when the server does not list the
+// archive formats it accepts, in which case callers should upload a zip
+var ErrUploadFormatsUnsupported = errors.New("archive format negotiation is not supported by this server")
+
+// UploadError is returned when S3 rejects a part or blob upload
+type UploadError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *UploadError) Error() string {
+	return fmt.Sprintf("upload failed (status %d): %s", e.StatusCode, e.Body)
+}
+
+// GetUploadFormats returns the archive formats the server accepts, most
+// preferred first, e.g. ["tar.zst", "tar.gz", "zip"]
+func (c *Client) GetUploadFormats(ctx context.Context) ([]string, error) {
+	resp, err := c.makeRequestContext(ctx, "GET", "/api/uploads/formats", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNotImplemented {
+		return nil, ErrUploadFormatsUnsupported
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("get upload formats failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var formatsResp UploadFormatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&formatsResp); err != nil {
+		return nil, err
+	}
+
+	return formatsResp.Formats, nil
+}
+
+// CreateMultipartUpload starts a multipart upload of a project archive
+func (c *Client) CreateMultipartUpload(ctx context.Context, projectID string, req MultipartUploadRequest) (*MultipartUpload, error) {
+	resp, err := c.makeRequestContext(ctx, "POST", fmt.Sprintf("/api/projects/%s/multipart-upload", projectID), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNotImplemented {
+		return nil, ErrMultipartUnsupported
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("create multipart upload failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var upload MultipartUpload
+	if err := json.NewDecoder(resp.Body).Decode(&upload); err != nil {
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+// GetPartUploadURLs gets pre-signed URLs for the given part numbers
+func (c *Client) GetPartUploadURLs(ctx context.Context, projectID, uploadID string, partNumbers []int) ([]PartURL, error) {
+	req := PartURLsRequest{PartNumbers: partNumbers}
+
+	resp, err := c.makeRequestContext(ctx, "POST", fmt.Sprintf("/api/projects/%s/multipart-upload/%s/parts", projectID, uploadID), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("multipart upload not found")
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("get part upload URLs failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var partsResp PartURLsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&partsResp); err != nil {
+		return nil, err
+	}
+
+	return partsResp.Parts, nil
+}
+
+// UploadPart streams size bytes from body to a pre-signed part URL and returns
+// the part's ETag
+func (c *Client) UploadPart(ctx context.Context, presignedURL string, body io.Reader, size int64) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "PUT", presignedURL, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create part upload request: %w", err)
+	}
+	req.ContentLength = size
+
+	// No overall timeout: a part is bounded by its size and ctx cancels it
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("part upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", &UploadError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		return "", fmt.Errorf("part upload failed: response has no ETag header")
+	}
+
+	return strings.Trim(etag, `"`), nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into the archive
+func (c *Client) CompleteMultipartUpload(ctx context.Context, projectID, uploadID string, parts []CompletedPart) (*CompleteMultipartUploadResponse, error) {
+	req := CompleteMultipartUploadRequest{Parts: parts}
+
+	resp, err := c.makeRequestContext(ctx, "POST", fmt.Sprintf("/api/projects/%s/multipart-upload/%s/complete", projectID, uploadID), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("complete multipart upload failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var completeResp CompleteMultipartUploadResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completeResp); err != nil {
+		return nil, err
+	}
+
+	return &completeResp, nil
+}
+
+// AbortMultipartUpload discards a multipart upload and its uploaded parts
+func (c *Client) AbortMultipartUpload(ctx context.Context, projectID, uploadID string) error {
+	resp, err := c.makeRequestContext(ctx, "DELETE", fmt.Sprintf("/api/projects/%s/multipart-upload/%s", projectID, uploadID), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("abort multipart upload failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// GetMissingBlobs returns upload URLs for the content hashes the server does
+// not have yet
+func (c *Client) GetMissingBlobs(ctx context.Context, projectID string, hashes []string) ([]BlobUploadURL, error) {
+	req := MissingBlobsRequest{Hashes: hashes}
+
+	resp, err := c.makeRequestContext(ctx, "POST", fmt.Sprintf("/api/projects/%s/blobs/missing", projectID), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNotImplemented {
+		return nil, ErrIncrementalUnsupported
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("check blobs failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var missingResp MissingBlobsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&missingResp); err != nil {
+		return nil, err
+	}
+
+	return missingResp.Missing, nil
+}
+
+// UploadBlob streams size bytes of file content to a pre-signed blob URL
+func (c *Client) UploadBlob(ctx context.Context, presignedURL string, body io.Reader, size int64) error {
+	req, err := http.NewRequestWithContext(ctx, "PUT", presignedURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create blob upload request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.ContentLength = size
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("blob upload failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		respBody, _ := io.ReadAll(resp.Body)
+		return &UploadError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	return nil
+}
+
+// CommitManifest assembles the project source from uploaded blobs
+func (c *Client) CommitManifest(ctx context.Context, projectID string, files []ManifestFile) (*CommitManifestResponse, error) {
+	req := CommitManifestRequest{Files: files}
+
+	resp, err := c.makeRequestContext(ctx, "POST", fmt.Sprintf("/api/projects/%s/manifest", projectID), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusNotImplemented {
+		return nil, ErrIncrementalUnsupported
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("commit manifest failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var commitResp CommitManifestResponse
+	if err := json.NewDecoder(resp.Body).Decode(&commitResp); err != nil {
+		return nil, err
+	}
+
+	return &commitResp, nil
+}
@@ -0,0 +1,268 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scrypt parameters for new credential files. They are stored in the file,
+// so they can be raised later without breaking existing files.
+const (
+	scryptN       = 1 << 15
+	scryptR       = 8
+	scryptP       = 1
+	scryptKeySize = 32
+	scryptSaltLen = 16
+)
+
+// credentialFileVersion is the format version of the credentials file
+const credentialFileVersion = 1
+
+// passphraseCheck is encrypted into every file so a wrong passphrase is
+// reported as such, even before any credential is stored
+const passphraseCheck = "leanmcp-cli"
+
+// credentialFile is the on-disk form of the encrypted credentials file. Byte
+// slices are base64 encoded by encoding/json.
+type credentialFile struct {
+	Version int          `json:"version"`
+	KDF     scryptParams `json:"kdf"`
+	// Check is passphraseCheck encrypted with the derived key
+	Check []byte `json:"check"`
+	// Credentials maps account names to nonce-prefixed AES-GCM ciphertexts
+	Credentials map[string][]byte `json:"credentials"`
+}
+
+// scryptParams records how the file key is derived from the passphrase
+type scryptParams struct {
+	Salt []byte `json:"salt"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+}
+
+// fileStore keeps secrets in a file encrypted with AES-GCM under a key
+// derived from a passphrase with scrypt. The passphrase comes from
+// LEANMCP_PASSPHRASE or PromptPassphrase.
+type fileStore struct {
+	path string
+	key  []byte // derived on first use
+}
+
+func newFileStore(path string) *fileStore {
+	return &fileStore{path: path}
+}
+
+func (s *fileStore) Name() string {
+	return StoreFile
+}
+
+func (s *fileStore) Get(account string) (string, error) {
+	file, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	if file == nil {
+		return "", ErrCredentialNotFound
+	}
+	ciphertext, ok := file.Credentials[account]
+	if !ok {
+		return "", ErrCredentialNotFound
+	}
+
+	gcm, err := s.cipher(file, false)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := openSealed(gcm, ciphertext, account)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s: %w", s.path, err)
+	}
+	return string(plaintext), nil
+}
+
+func (s *fileStore) Set(account, secret string) error {
+	file, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	created := file == nil
+	if created {
+		salt := make([]byte, scryptSaltLen)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return err
+		}
+		file = &credentialFile{
+			Version:     credentialFileVersion,
+			KDF:         scryptParams{Salt: salt, N: scryptN, R: scryptR, P: scryptP},
+			Credentials: make(map[string][]byte),
+		}
+	}
+
+	gcm, err := s.cipher(file, created)
+	if err != nil {
+		return err
+	}
+	if created {
+		file.Check, err = seal(gcm, []byte(passphraseCheck), "")
+		if err != nil {
+			return err
+		}
+	}
+
+	file.Credentials[account], err = seal(gcm, []byte(secret), account)
+	if err != nil {
+		return err
+	}
+	return s.save(file)
+}
+
+func (s *fileStore) Delete(account string) error {
+	file, err := s.load()
+	if err != nil || file == nil {
+		return err
+	}
+	if _, ok := file.Credentials[account]; !ok {
+		return nil
+	}
+
+	// Removing an entry needs no key
+	delete(file.Credentials, account)
+	if len(file.Credentials) == 0 {
+		return os.Remove(s.path)
+	}
+	return s.save(file)
+}
+
+// load reads the credentials file, returning nil if it does not exist yet
+func (s *fileStore) load() (*credentialFile, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var file credentialFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid credentials file %s: %w", s.path, err)
+	}
+	if file.Version != credentialFileVersion {
+		return nil, fmt.Errorf("credentials file %s has unsupported version %d", s.path, file.Version)
+	}
+	if file.Credentials == nil {
+		file.Credentials = make(map[string][]byte)
+	}
+	return &file, nil
+}
+
+// save writes the file atomically and readable by the owner only
+func (s *fileStore) save(file *credentialFile) error {
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".credentials-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// cipher derives the file key from the passphrase and checks it against the
+// file. create asks for a new passphrase to be confirmed.
+func (s *fileStore) cipher(file *credentialFile, create bool) (cipher.AEAD, error) {
+	if s.key == nil {
+		passphrase, err := readPassphrase(create)
+		if err != nil {
+			return nil, err
+		}
+
+		kdf := file.KDF
+		key, err := scrypt.Key([]byte(passphrase), kdf.Salt, kdf.N, kdf.R, kdf.P, scryptKeySize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+		s.key = key
+	}
+
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if !create {
+		if _, err := openSealed(gcm, file.Check, ""); err != nil {
+			s.key = nil
+			return nil, errors.New("wrong passphrase for the credentials file")
+		}
+	}
+	return gcm, nil
+}
+
+// readPassphrase returns LEANMCP_PASSPHRASE or asks for the passphrase
+func readPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv("LEANMCP_PASSPHRASE"); passphrase != "" {
+		return passphrase, nil
+	}
+	if PromptPassphrase == nil {
+		return "", errors.New("the credentials file is encrypted; set LEANMCP_PASSPHRASE or run in a terminal")
+	}
+
+	passphrase, err := PromptPassphrase(confirm)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(passphrase) == "" {
+		return "", errors.New("passphrase cannot be empty")
+	}
+	return passphrase, nil
+}
+
+// seal encrypts plaintext with a random nonce, binding it to the account so
+// entries cannot be swapped between accounts
+func seal(gcm cipher.AEAD, plaintext []byte, account string) ([]byte, error) {
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, []byte(account)), nil
+}
+
+// openSealed decrypts the output of seal
+func openSealed(gcm cipher.AEAD, data []byte, account string) ([]byte, error) {
+	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.New("ciphertext too short")
+	}
+	return gcm.Open(nil, data[:nonceSize], data[nonceSize:], []byte(account))
+}
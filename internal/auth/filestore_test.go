@@ -0,0 +1,259 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ddod/leanmcp-cli/internal/config"
+	"github.com/spf13/viper"
+)
+
+// newTestFileStore returns a file store in a temp dir, unlocked by passphrase
+func newTestFileStore(t *testing.T, passphrase string) *fileStore {
+	t.Helper()
+	t.Setenv("LEANMCP_PASSPHRASE", passphrase)
+	return newFileStore(filepath.Join(t.TempDir(), credentialsFileName))
+}
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	store := newTestFileStore(t, "correct horse")
+
+	if err := store.Set("default", "airtrain_secret_1"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := store.Set("work", "airtrain_secret_2"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// A new store derives the key from the file again
+	reopened := newFileStore(store.path)
+	for account, want := range map[string]string{"default": "airtrain_secret_1", "work": "airtrain_secret_2"} {
+		got, err := reopened.Get(account)
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v", account, err)
+		}
+		if got != want {
+			t.Errorf("Get(%q) = %q, want %q", account, got, want)
+		}
+	}
+
+	data, err := os.ReadFile(store.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("airtrain_secret")) {
+		t.Error("credentials file contains a plaintext secret")
+	}
+	info, err := os.Stat(store.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("credentials file mode = %o, want 600", mode)
+	}
+}
+
+func TestFileStoreWrongPassphrase(t *testing.T) {
+	store := newTestFileStore(t, "correct horse")
+	if err := store.Set("default", "airtrain_secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("LEANMCP_PASSPHRASE", "battery staple")
+	_, err := newFileStore(store.path).Get("default")
+	if err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("Get() error = %v, want a wrong passphrase error", err)
+	}
+	if err := newFileStore(store.path).Set("other", "x"); err == nil {
+		t.Error("Set() with the wrong passphrase succeeded")
+	}
+}
+
+func TestFileStoreEntriesAreBoundToAccounts(t *testing.T) {
+	store := newTestFileStore(t, "correct horse")
+	if err := store.Set("default", "airtrain_default"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("work", "airtrain_work"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Swap the two ciphertexts in the file
+	file, err := store.load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Credentials["default"], file.Credentials["work"] = file.Credentials["work"], file.Credentials["default"]
+	data, err := json.Marshal(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(store.path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, account := range []string{"default", "work"} {
+		if got, err := newFileStore(store.path).Get(account); err == nil {
+			t.Errorf("Get(%q) = %q after swapping entries, want an error", account, got)
+		}
+	}
+}
+
+func TestFileStoreDelete(t *testing.T) {
+	store := newTestFileStore(t, "correct horse")
+	if err := store.Set("default", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("work", "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Deleting a missing entry is not an error
+	if err := store.Delete("missing"); err != nil {
+		t.Errorf("Delete(missing) error = %v", err)
+	}
+
+	if err := store.Delete("work"); err != nil {
+		t.Fatalf("Delete(work) error = %v", err)
+	}
+	if _, err := store.Get("work"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("Get(work) after Delete error = %v, want ErrCredentialNotFound", err)
+	}
+	if got, err := store.Get("default"); err != nil || got != "a" {
+		t.Errorf("Get(default) = %q, %v, want the remaining entry", got, err)
+	}
+
+	// Deleting the last entry removes the file
+	if err := store.Delete("default"); err != nil {
+		t.Fatalf("Delete(default) error = %v", err)
+	}
+	if _, err := os.Stat(store.path); !os.IsNotExist(err) {
+		t.Errorf("credentials file still exists after deleting the last entry: %v", err)
+	}
+	if err := store.Delete("default"); err != nil {
+		t.Errorf("Delete() without a file error = %v", err)
+	}
+}
+
+func TestFileStoreMissing(t *testing.T) {
+	store := newTestFileStore(t, "correct horse")
+	if _, err := store.Get("default"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("Get() without a file error = %v, want ErrCredentialNotFound", err)
+	}
+}
+
+func TestFileStoreNeedsPassphrase(t *testing.T) {
+	store := newTestFileStore(t, "")
+	defer func(prompt func(bool) (string, error)) { PromptPassphrase = prompt }(PromptPassphrase)
+	PromptPassphrase = nil
+
+	if err := store.Set("default", "a"); err == nil {
+		t.Error("Set() without a passphrase succeeded")
+	}
+	if _, err := os.Stat(store.path); !os.IsNotExist(err) {
+		t.Errorf("credentials file was written without a passphrase: %v", err)
+	}
+}
+
+// setupTestConfig points the config at a temp dir and returns the dir
+func setupTestConfig(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	t.Setenv(APIKeyEnv, "")
+	if err := config.Initialize(filepath.Join(dir, "config.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// encryptLegacyAPIKey stores apiKey the way versions before the credential
+// store did
+func encryptLegacyAPIKey(t *testing.T, apiKey string) string {
+	t.Helper()
+	block, err := aes.NewCipher(deriveLegacyKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(apiKey), nil))
+}
+
+func TestMigrateLegacyCredentials(t *testing.T) {
+	dir := setupTestConfig(t)
+	t.Setenv("LEANMCP_PASSPHRASE", "correct horse")
+	config.SetProfileString("credential_store", StoreFile)
+	config.SetProfileString("api_key", encryptLegacyAPIKey(t, "airtrain_legacy"))
+	credentialsPath := filepath.Join(dir, credentialsFileName)
+
+	// Loading uses the legacy key without moving it
+	creds, err := LoadCredentials()
+	if err != nil {
+		t.Fatalf("LoadCredentials() error = %v", err)
+	}
+	if creds.APIKey != "airtrain_legacy" || creds.Store != "" {
+		t.Errorf("LoadCredentials() = %q from %q, want the legacy key from the config file", creds.APIKey, creds.Store)
+	}
+	if !HasLegacyAPIKey() {
+		t.Error("LoadCredentials() moved the legacy key")
+	}
+	if _, err := os.Stat(credentialsPath); !os.IsNotExist(err) {
+		t.Errorf("LoadCredentials() created the credentials file: %v", err)
+	}
+
+	store, err := MigrateLegacyCredentials()
+	if err != nil {
+		t.Fatalf("MigrateLegacyCredentials() error = %v", err)
+	}
+	if store != StoreFile {
+		t.Errorf("MigrateLegacyCredentials() store = %q, want %q", store, StoreFile)
+	}
+	if HasLegacyAPIKey() {
+		t.Error("legacy key is still in the config file")
+	}
+
+	creds, err = LoadCredentials()
+	if err != nil {
+		t.Fatalf("LoadCredentials() after migrating error = %v", err)
+	}
+	if creds.APIKey != "airtrain_legacy" || creds.Store != StoreFile {
+		t.Errorf("LoadCredentials() = %q from %q, want the key from %q", creds.APIKey, creds.Store, StoreFile)
+	}
+
+	// Nothing is left to move
+	if store, err := MigrateLegacyCredentials(); store != "" || err != nil {
+		t.Errorf("second MigrateLegacyCredentials() = %q, %v, want nothing to do", store, err)
+	}
+}
+
+func TestLoadCredentialsUndecryptableLegacyKey(t *testing.T) {
+	setupTestConfig(t)
+	config.SetProfileString("api_key", base64.StdEncoding.EncodeToString([]byte("from another machine, not a ciphertext")))
+
+	if _, err := LoadCredentials(); err == nil {
+		t.Error("LoadCredentials() succeeded with a key that cannot be decrypted")
+	}
+	if _, err := MigrateLegacyCredentials(); err == nil {
+		t.Error("MigrateLegacyCredentials() succeeded with a key that cannot be decrypted")
+	}
+	if !HasLegacyAPIKey() {
+		t.Error("the undecryptable key was removed")
+	}
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ddod/leanmcp-cli/internal/config"
+)
+
+// APIKeyEnv is the environment variable that supplies an API key without
+// storing it. It takes precedence over credential helpers and stored keys.
+const APIKeyEnv = "LEANMCP_API_KEY"
+
+// Sources of API keys that are used without being stored
+const (
+	SourceEnv    = "env"    // the LEANMCP_API_KEY environment variable
+	SourceHelper = "helper" // the program set as credential_helper
+)
+
+// runCredentialHelper gets the API key of the active profile from an external
+// program, the way git credential helpers work. The command is split on
+// whitespace and run without a shell, with "get" appended. It receives
+// "profile=<name>" and "base_url=<url>" lines on stdin and prints the key,
+// either alone on the first line or as an "api_key=<key>" line. Its stderr is
+// passed through, so it can prompt the user.
+func runCredentialHelper(command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", errors.New("credential_helper is empty")
+	}
+
+	var input bytes.Buffer
+	fmt.Fprintf(&input, "profile=%s\n", config.ActiveProfile())
+	if baseURL, err := config.GetBaseURL(); err == nil {
+		fmt.Fprintf(&input, "base_url=%s\n", baseURL)
+	}
+
+	helper := exec.Command(args[0], append(args[1:], "get")...)
+	helper.Stdin = &input
+	helper.Stderr = os.Stderr
+	output, err := helper.Output()
+	if err != nil {
+		return "", fmt.Errorf("credential helper %q failed: %w", args[0], err)
+	}
+
+	apiKey := parseHelperOutput(output)
+	if apiKey == "" {
+		return "", fmt.Errorf("credential helper %q returned no API key", args[0])
+	}
+	return apiKey, nil
+}
+
+// parseHelperOutput returns the api_key attribute of the helper output, or its
+// first line when it has no attributes
+func parseHelperOutput(output []byte) string {
+	var first string
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for i := 0; scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if value, ok := strings.CutPrefix(line, "api_key="); ok {
+			return strings.TrimSpace(value)
+		}
+		if i == 0 {
+			first = line
+		}
+	}
+	if strings.Contains(first, "=") {
+		return ""
+	}
+	return first
+}
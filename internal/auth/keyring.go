@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/ddod/leanmcp-cli/internal/config"
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is the service name secrets are stored under in the keyring
+const keyringService = "leanmcp-cli"
+
+// keyringStore keeps secrets in the OS keyring: the Secret Service over D-Bus
+// on Linux, the Keychain on macOS and the Credential Manager on Windows
+type keyringStore struct{}
+
+func newKeyringStore() *keyringStore {
+	return &keyringStore{}
+}
+
+// keyringAvailable reports whether the keyring can be reached. A lookup of a
+// missing secret succeeds with ErrNotFound; without a Secret Service provider
+// or D-Bus session it fails with another error.
+func keyringAvailable() bool {
+	_, err := keyring.Get(keyringService, config.DefaultProfile)
+	return err == nil || errors.Is(err, keyring.ErrNotFound)
+}
+
+func (s *keyringStore) Name() string {
+	return StoreKeyring
+}
+
+func (s *keyringStore) Get(account string) (string, error) {
+	secret, err := keyring.Get(keyringService, account)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrCredentialNotFound
+	}
+	return secret, err
+}
+
+func (s *keyringStore) Set(account, secret string) error {
+	return keyring.Set(keyringService, account, secret)
+}
+
+func (s *keyringStore) Delete(account string) error {
+	err := keyring.Delete(keyringService, account)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
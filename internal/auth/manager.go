@@ -3,12 +3,10 @@ package auth
 import (
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"strings"
 	"time"
@@ -16,19 +14,33 @@ import (
 	"github.com/ddod/leanmcp-cli/internal/config"
 )
 
+// ErrNoCredentials is returned by LoadCredentials when no API key is stored
+var ErrNoCredentials = errors.New("no stored credentials found")
+
 // Credentials holds the authentication information
 type Credentials struct {
-	APIKey      string    `yaml:"api_key"`
-	UserEmail   string    `yaml:"user_email,omitempty"`
-	Scopes      []string  `yaml:"scopes,omitempty"`
-	StoredAt    time.Time `yaml:"stored_at"`
-	LastUsed    time.Time `yaml:"last_used,omitempty"`
+	APIKey      string     `yaml:"api_key"`
+	UserEmail   string     `yaml:"user_email,omitempty"`
+	Scopes      []string   `yaml:"scopes,omitempty"`
+	StoredAt    time.Time  `yaml:"stored_at"`
+	LastUsed    time.Time  `yaml:"last_used,omitempty"`
+	Store       string     `yaml:"-"` // credential store backend holding the key
+	KeyName     string     `yaml:"key_name,omitempty"`
+	ExpiresAt   *time.Time `yaml:"expires_at,omitempty"` // nil if the key never expires
+}
+
+// External reports whether the API key comes from LEANMCP_API_KEY or a
+// credential helper rather than the credential store
+func (c *Credentials) External() bool {
+	return c.Store == SourceEnv || c.Store == SourceHelper
 }
 
 // UserInfo represents user information returned from API
 type UserInfo struct {
-	Email  string   `json:"email"`
-	Scopes []string `json:"scopes"`
+	Email     string     `json:"email"`
+	Scopes    []string   `json:"scopes"`
+	KeyName   string     `json:"keyName"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
 // ValidateAPIKeyFormat checks if the API key has the correct format
@@ -48,10 +60,11 @@ func ValidateAPIKeyFormat(apiKey string) error {
 	return nil
 }
 
-// StoreCredentials encrypts and stores the API key and user info
-func StoreCredentials(apiKey string, userInfo *UserInfo) error {
+// StoreCredentials stores the API key in the credential store and the user
+// info in the config file
+func StoreCredentials(apiKey string, userInfo *UserInfo) (*Credentials, error) {
 	if err := ValidateAPIKeyFormat(apiKey); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Create credentials
@@ -63,108 +76,202 @@ func StoreCredentials(apiKey string, userInfo *UserInfo) error {
 	if userInfo != nil {
 		creds.UserEmail = userInfo.Email
 		creds.Scopes = userInfo.Scopes
+		creds.KeyName = userInfo.KeyName
+		creds.ExpiresAt = userInfo.ExpiresAt
 	}
 
-	// Encrypt and store
-	encryptedKey, err := encryptAPIKey(apiKey)
+	// Store the key outside the config file
+	store, err := saveAPIKey(apiKey)
 	if err != nil {
-		return fmt.Errorf("failed to encrypt API key: %v", err)
+		return nil, err
 	}
+	creds.Store = store
 
 	// Store in config
-	config.SetString("api_key", encryptedKey)
-	config.SetString("user_email", creds.UserEmail)
-	config.SetString("stored_at", creds.StoredAt.Format(time.RFC3339))
+	config.SetProfileString("user_email", creds.UserEmail)
+	config.SetProfileString("stored_at", creds.StoredAt.Format(time.RFC3339))
+	config.SetProfileString("key_name", creds.KeyName)
 	
 	// Save scopes as comma-separated string
-	if len(creds.Scopes) > 0 {
-		config.SetString("scopes", strings.Join(creds.Scopes, ","))
+	config.SetProfileString("scopes", strings.Join(creds.Scopes, ","))
+
+	// An empty expiry means the key never expires
+	expiresAt := ""
+	if creds.ExpiresAt != nil {
+		expiresAt = creds.ExpiresAt.Format(time.RFC3339)
 	}
+	config.SetProfileString("expires_at", expiresAt)
 
-	return config.SaveConfig()
+	return creds, config.SaveConfig()
 }
 
-// LoadCredentials loads and decrypts the stored credentials
+// LoadCredentials loads the API key from LEANMCP_API_KEY, the credential
+// helper or the credential store, in that order. A key stored in the config
+// file by older versions is still used from there, with an empty Store, until
+// MigrateLegacyCredentials moves it.
 func LoadCredentials() (*Credentials, error) {
-	encryptedKey := config.GetString("api_key")
-	if encryptedKey == "" {
-		return nil, errors.New("no stored credentials found")
+	// Keys from the environment or a helper are used without being stored
+	if apiKey := strings.TrimSpace(os.Getenv(APIKeyEnv)); apiKey != "" {
+		return &Credentials{APIKey: apiKey, Store: SourceEnv}, nil
+	}
+	if helper := config.GetProfileString("credential_helper"); helper != "" {
+		apiKey, err := runCredentialHelper(helper)
+		if err != nil {
+			return nil, err
+		}
+		return &Credentials{APIKey: apiKey, Store: SourceHelper}, nil
 	}
 
-	// Decrypt API key
-	apiKey, err := decryptAPIKey(encryptedKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt API key: %v", err)
+	var apiKey, storeName string
+	if HasLegacyAPIKey() {
+		key, err := legacyAPIKey()
+		if err != nil {
+			return nil, err
+		}
+		apiKey = key
+	} else {
+		storeName = config.GetProfileString("credential_store")
+		if storeName == "" {
+			return nil, ErrNoCredentials
+		}
+		
+		store, err := OpenCredentialStore(storeName)
+		if err != nil {
+			return nil, err
+		}
+		apiKey, err = store.Get(config.ActiveProfile())
+		if errors.Is(err, ErrCredentialNotFound) {
+			return nil, ErrNoCredentials
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read API key from %s: %w", store.Name(), err)
+		}
 	}
 
 	// Load other data
 	creds := &Credentials{
 		APIKey:    apiKey,
-		UserEmail: config.GetString("user_email"),
+		UserEmail: config.GetProfileString("user_email"),
+		Store:     storeName,
+		KeyName:   config.GetProfileString("key_name"),
 	}
 
 	// Parse stored_at
-	if storedAtStr := config.GetString("stored_at"); storedAtStr != "" {
+	if storedAtStr := config.GetProfileString("stored_at"); storedAtStr != "" {
 		if t, err := time.Parse(time.RFC3339, storedAtStr); err == nil {
 			creds.StoredAt = t
 		}
 	}
 
 	// Parse scopes
-	if scopesStr := config.GetString("scopes"); scopesStr != "" {
+	if scopesStr := config.GetProfileString("scopes"); scopesStr != "" {
 		creds.Scopes = strings.Split(scopesStr, ",")
 	}
 
+	// Parse expires_at
+	if expiresAtStr := config.GetProfileString("expires_at"); expiresAtStr != "" {
+		if t, err := time.Parse(time.RFC3339, expiresAtStr); err == nil {
+			creds.ExpiresAt = &t
+		}
+	}
+
 	return creds, nil
 }
 
+// HasLegacyAPIKey reports whether the active profile still has an API key in
+// the config file, as stored by versions before the credential store
+func HasLegacyAPIKey() bool {
+	return config.GetProfileString("api_key") != ""
+}
+
+// MigrateLegacyCredentials moves an API key stored in the config file by
+// older versions to the credential store and returns the name of the store.
+// Opening the encrypted file may ask for a new passphrase, so this is only
+// done where the user expects it. It returns "" if there is nothing to move.
+func MigrateLegacyCredentials() (string, error) {
+	if !HasLegacyAPIKey() {
+		return "", nil
+	}
+	apiKey, err := legacyAPIKey()
+	if err != nil {
+		return "", err
+	}
+	return saveAPIKey(apiKey)
+}
+
+// legacyAPIKey decrypts the API key stored in the config file
+func legacyAPIKey() (string, error) {
+	apiKey, err := decryptLegacyAPIKey(config.GetProfileString("api_key"))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt the API key in the config file, which only works on the machine it was stored on; run 'leanmcp auth login' again: %v", err)
+	}
+	return apiKey, nil
+}
+
 // ClearCredentials removes stored credentials
 func ClearCredentials() error {
-	config.SetString("api_key", "")
-	config.SetString("user_email", "")
-	config.SetString("stored_at", "")
-	config.SetString("scopes", "")
+	if storeName := config.GetProfileString("credential_store"); storeName != "" {
+		store, err := OpenCredentialStore(storeName)
+		if err != nil {
+			return err
+		}
+		if err := store.Delete(config.ActiveProfile()); err != nil {
+			return fmt.Errorf("failed to remove API key from %s: %w", store.Name(), err)
+		}
+	}
+	
+	config.SetProfileString("api_key", "")
+	config.SetProfileString("user_email", "")
+	config.SetProfileString("stored_at", "")
+	config.SetProfileString("scopes", "")
+	config.SetProfileString("key_name", "")
+	config.SetProfileString("expires_at", "")
 	return config.SaveConfig()
 }
 
+// RemoveStoredKey deletes the API key of the active profile from a credential
+// store it is no longer kept in, leaving the config file alone
+func RemoveStoredKey(storeName string) error {
+	store, err := OpenCredentialStore(storeName)
+	if err != nil {
+		return err
+	}
+	return store.Delete(config.ActiveProfile())
+}
+
 // UpdateLastUsed updates the last used timestamp
 func UpdateLastUsed() error {
-	config.SetString("last_used", time.Now().Format(time.RFC3339))
+	config.SetProfileString("last_used", time.Now().Format(time.RFC3339))
 	return config.SaveConfig()
 }
 
-// encryptAPIKey encrypts the API key using AES
-func encryptAPIKey(apiKey string) (string, error) {
-	// Use a simple key derivation from machine info
-	key := deriveKey()
-	
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return "", err
-	}
-
-	gcm, err := cipher.NewGCM(block)
+// saveAPIKey writes the API key to the configured credential store, or the
+// best available one, and removes any legacy key from the config file. It
+// returns the name of the store used.
+func saveAPIKey(apiKey string) (string, error) {
+	store, err := OpenCredentialStore(config.GetProfileString("credential_store"))
 	if err != nil {
 		return "", err
 	}
-
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", err
+	
+	if err := store.Set(config.ActiveProfile(), apiKey); err != nil {
+		return "", fmt.Errorf("failed to store API key in %s: %v", store.Name(), err)
 	}
-
-	ciphertext := gcm.Seal(nonce, nonce, []byte(apiKey), nil)
-	return base64.StdEncoding.EncodeToString(ciphertext), nil
+	
+	config.SetProfileString("credential_store", store.Name())
+	config.SetProfileString("api_key", "")
+	return store.Name(), config.SaveConfig()
 }
 
-// decryptAPIKey decrypts the API key
-func decryptAPIKey(encryptedKey string) (string, error) {
+// decryptLegacyAPIKey decrypts an API key stored in the config file by
+// versions before the credential store
+func decryptLegacyAPIKey(encryptedKey string) (string, error) {
 	data, err := base64.StdEncoding.DecodeString(encryptedKey)
 	if err != nil {
 		return "", err
 	}
 
-	key := deriveKey()
+	key := deriveLegacyKey()
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return "", err
@@ -189,8 +296,10 @@ func decryptAPIKey(encryptedKey string) (string, error) {
 	return string(plaintext), nil
 }
 
-// deriveKey creates a key from machine-specific information
-func deriveKey() []byte {
+// deriveLegacyKey recreates the key legacy API keys were encrypted with. It
+// only depends on the hostname, which is why keys are no longer stored this
+// way.
+func deriveLegacyKey() []byte {
 	hostname, _ := os.Hostname()
 	data := fmt.Sprintf("leanmcp-cli-%s", hostname)
 	hash := sha256.Sum256([]byte(data))
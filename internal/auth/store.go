@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ddod/leanmcp-cli/internal/config"
+)
+
+// Credential store backends
+const (
+	StoreAuto    = "auto"    // the keyring if it is available, else the file
+	StoreKeyring = "keyring" // the OS keyring (Secret Service, Keychain, Credential Manager)
+	StoreFile    = "file"    // a passphrase-encrypted file next to the config file
+)
+
+// credentialsFileName is the name of the encrypted file in the config directory
+const credentialsFileName = "credentials.json"
+
+// ErrCredentialNotFound is returned by a CredentialStore that holds no secret
+// for the requested account
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// CredentialStore keeps secrets such as API keys outside the config file. API
+// keys are stored under the name of their profile as the account.
+type CredentialStore interface {
+	// Name returns the backend name, StoreKeyring or StoreFile
+	Name() string
+	// Get returns the secret of an account or ErrCredentialNotFound
+	Get(account string) (string, error)
+	// Set stores the secret of an account, replacing any previous one
+	Set(account, secret string) error
+	// Delete removes the secret of an account; deleting a missing one is not
+	// an error
+	Delete(account string) error
+}
+
+// PromptPassphrase asks the user for the passphrase of the credentials file.
+// confirm is set when a new file is created and the passphrase should be
+// entered twice. It is nil when no terminal is available, in which case the
+// passphrase must come from LEANMCP_PASSPHRASE.
+var PromptPassphrase func(confirm bool) (string, error)
+
+// StoreDescription describes where a backend keeps secrets, for messages
+func StoreDescription(name string) string {
+	switch name {
+	case StoreKeyring:
+		return "the OS keyring"
+	case StoreFile:
+		return "an encrypted file (" + credentialsFileName + " in the config directory)"
+	case SourceEnv:
+		return "the " + APIKeyEnv + " environment variable"
+	case SourceHelper:
+		return "the credential helper"
+	case "":
+		return "the config file"
+	default:
+		return name
+	}
+}
+
+// OpenCredentialStore opens a backend by name. StoreAuto, or an empty name,
+// picks the OS keyring when it can be reached and the encrypted file
+// otherwise.
+func OpenCredentialStore(name string) (CredentialStore, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", StoreAuto:
+		if keyringAvailable() {
+			return newKeyringStore(), nil
+		}
+		return openFileStore()
+	case StoreKeyring:
+		if !keyringAvailable() {
+			return nil, errors.New("the OS keyring is not available (on Linux, a Secret Service provider such as gnome-keyring must be running)")
+		}
+		return newKeyringStore(), nil
+	case StoreFile:
+		return openFileStore()
+	default:
+		return nil, fmt.Errorf("unknown credential store %q (use auto, keyring or file)", name)
+	}
+}
+
+// openFileStore opens the encrypted file in the config directory
+func openFileStore() (CredentialStore, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return newFileStore(filepath.Join(dir, credentialsFileName)), nil
+}
@@ -28,9 +28,6 @@ func Initialize(cfgFile string) error {
 	}
 
 	viper.AutomaticEnv()
-	
-	// Set defaults
-	viper.SetDefault("base_url", "https://api.leanmcp.ai")
 
 	// Try to read config, but don't fail if it doesn't exist
 	_ = viper.ReadInConfig()
@@ -45,18 +42,75 @@ func GetString(key string) string {
 
 // SetString sets a config value
 func SetString(key, value string) {
+	set(key, value)
+}
+
+// changes are the settings changed in this run. Only these are added to the
+// config file when saving: viper's merged view also holds flags such as --env
+// and environment variables picked up by AutomaticEnv, which must not be
+// written back.
+var changes = make(map[string]interface{})
+
+// set changes a setting for this run and marks it to be saved
+func set(key string, value interface{}) {
 	viper.Set(key, value)
+	changes[key] = value
 }
 
-// SaveConfig saves the current config to file
+// SaveConfig writes the config file as read from disk plus the settings
+// changed in this run
 func SaveConfig() error {
-	// Try to write config first
-	err := viper.WriteConfig()
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		dir, err := Dir()
+		if err != nil {
+			return err
+		}
+		path = filepath.Join(dir, "config.yaml")
+	}
+	
+	onDisk := viper.New()
+	onDisk.SetConfigFile(path)
+	if filepath.Ext(path) == "" {
+		onDisk.SetConfigType("yaml")
+	}
+	_ = onDisk.ReadInConfig()
+	for key, value := range changes {
+		onDisk.Set(key, value)
+	}
+	
+	settings := onDisk.AllSettings()
+	if profiles, ok := settings["profiles"].(map[string]interface{}); ok {
+		for name := range deletedProfiles {
+			delete(profiles, name)
+		}
+	}
+	
+	out := viper.New()
+	if filepath.Ext(path) == "" {
+		out.SetConfigType("yaml")
+	}
+	if err := out.MergeConfigMap(settings); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return out.WriteConfigAs(path)
+}
+
+// Dir returns the directory holding the config file, where other CLI state
+// such as the encrypted credentials file is kept too
+func Dir() (string, error) {
+	if used := viper.ConfigFileUsed(); used != "" {
+		return filepath.Dir(used), nil
+	}
+	
+	home, err := os.UserHomeDir()
 	if err != nil {
-		// If config doesn't exist, use SafeWriteConfig to create it
-		return viper.SafeWriteConfig()
+		return "", err
 	}
-	return nil
+	return filepath.Join(home, ".leanmcp-cli"), nil
 }
 
 // WriteConfigAs saves config to a specific file
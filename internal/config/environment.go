@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ddod/leanmcp-cli/internal/api"
+	"github.com/spf13/viper"
+)
+
+// DefaultEnvironment is the environment used when none is selected
+const DefaultEnvironment = "prod"
+
+// Environment represents a named API backend the CLI can talk to
+type Environment struct {
+	Name    string
+	BaseURL string
+}
+
+// builtinEnvironments are the environments known without any configuration.
+// Staging has no public default and must be configured under
+// environments.staging.base_url before it can be used.
+var builtinEnvironments = map[string]string{
+	"prod":    api.DefaultBaseURL,
+	"staging": "",
+	"local":   "http://localhost:3000",
+}
+
+// GetEnvironment looks up a named environment, preferring an entry in the
+// config file (environments.<name>.base_url) over the built-in default
+func GetEnvironment(name string) (*Environment, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = DefaultEnvironment
+	}
+
+	baseURL := viper.GetString("environments." + name + ".base_url")
+	if baseURL == "" {
+		builtin, ok := builtinEnvironments[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown environment %q (available: %s)", name, strings.Join(ListEnvironments(), ", "))
+		}
+		baseURL = builtin
+	}
+
+	if baseURL == "" {
+		return nil, fmt.Errorf("environment %q has no base URL; set environments.%s.base_url in your config file", name, name)
+	}
+
+	return &Environment{Name: name, BaseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+// ListEnvironments returns the names of all built-in and configured environments
+func ListEnvironments() []string {
+	seen := make(map[string]bool)
+	for name := range builtinEnvironments {
+		seen[name] = true
+	}
+	for name := range viper.GetStringMap("environments") {
+		seen[strings.ToLower(name)] = true
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// GetBaseURL resolves the API base URL to use for requests.
+//
+// Precedence, highest first:
+//  1. api_url (the --api-url flag or LEANMCP_API_URL)
+//  2. environment (the --env flag or LEANMCP_ENV)
+//  3. environment of the active profile
+//  4. base_url of the active profile (for the default profile, base_url
+//     from the config file)
+//  5. the prod environment
+func GetBaseURL() (string, error) {
+	if apiURL := viper.GetString("api_url"); apiURL != "" {
+		return strings.TrimRight(apiURL, "/"), nil
+	}
+
+	envName := viper.GetString("environment")
+	if envName == "" {
+		envName = GetProfileString("environment")
+	}
+	if envName != "" {
+		env, err := GetEnvironment(envName)
+		if err != nil {
+			return "", err
+		}
+		return env.BaseURL, nil
+	}
+
+	if baseURL := GetProfileString("base_url"); baseURL != "" {
+		return strings.TrimRight(baseURL, "/"), nil
+	}
+
+	env, err := GetEnvironment(DefaultEnvironment)
+	if err != nil {
+		return "", err
+	}
+	return env.BaseURL, nil
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultProfile is the profile used when none is selected. Its settings are
+// kept at the top level of the config file, where versions without profiles
+// stored them; other profiles live under profiles.<name>.
+const DefaultProfile = "default"
+
+// profileNamePattern restricts names to ones that are safe as config keys
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// selectedProfile is set by UseProfile from --profile or LEANMCP_PROFILE
+var selectedProfile string
+
+// deletedProfiles are left out when the config is saved. Viper cannot unset
+// keys, so a removed profile would otherwise be read back from the file.
+var deletedProfiles = make(map[string]bool)
+
+// ValidateProfileName checks that a profile name can be stored
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// UseProfile selects the profile for this run, overriding current_profile.
+// An empty name keeps the current profile.
+func UseProfile(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name != "" {
+		if err := ValidateProfileName(name); err != nil {
+			return err
+		}
+	}
+	selectedProfile = name
+	return nil
+}
+
+// ActiveProfile returns the profile commands run as.
+//
+// Precedence, highest first:
+//  1. the --profile flag or LEANMCP_PROFILE
+//  2. current_profile from the config file (set by 'auth switch')
+//  3. the default profile
+func ActiveProfile() string {
+	if selectedProfile != "" {
+		return selectedProfile
+	}
+	return CurrentProfile()
+}
+
+// CurrentProfile returns the profile used when none is selected
+func CurrentProfile() string {
+	if current := viper.GetString("current_profile"); current != "" {
+		return current
+	}
+	return DefaultProfile
+}
+
+// ProfileKey returns the config key of a profile setting
+func ProfileKey(profile, key string) string {
+	if profile == DefaultProfile {
+		return key
+	}
+	return "profiles." + profile + "." + key
+}
+
+// GetProfileString gets a setting of the active profile
+func GetProfileString(key string) string {
+	return viper.GetString(ProfileKey(ActiveProfile(), key))
+}
+
+// SetProfileString sets a setting of the active profile
+func SetProfileString(key, value string) {
+	set(ProfileKey(ActiveProfile(), key), value)
+}
+
+// ListProfiles returns the default profile followed by the named profiles in
+// alphabetical order
+func ListProfiles() []string {
+	names := []string{DefaultProfile}
+
+	seen := make(map[string]bool)
+	for _, key := range viper.AllKeys() {
+		parts := strings.SplitN(key, ".", 3)
+		if len(parts) < 3 || parts[0] != "profiles" {
+			continue
+		}
+		name := parts[1]
+		if name != DefaultProfile && !deletedProfiles[name] && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names[1:])
+
+	return names
+}
+
+// ProfileExists reports whether a profile has any settings. The default
+// profile always exists.
+func ProfileExists(name string) bool {
+	for _, profile := range ListProfiles() {
+		if profile == name {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCurrentProfile makes a profile the one used when none is selected
+func SetCurrentProfile(name string) {
+	if name == DefaultProfile {
+		name = ""
+	}
+	set("current_profile", name)
+}
+
+// DeleteProfile removes a named profile and all of its settings. If it was the
+// current profile, the default profile becomes current.
+func DeleteProfile(name string) {
+	if name == DefaultProfile {
+		return
+	}
+	deletedProfiles[name] = true
+
+	if viper.GetString("current_profile") == name {
+		SetCurrentProfile(DefaultProfile)
+	}
+}
+
+// ProfileInfo summarizes a profile for listing
+type ProfileInfo struct {
+	Name            string `json:"name"`
+	Current         bool   `json:"current"`
+	LoggedIn        bool   `json:"loggedIn"`
+	UserEmail       string `json:"userEmail,omitempty"`
+	CredentialStore string `json:"credentialStore,omitempty"`
+	BaseURL         string `json:"baseUrl,omitempty"`
+	Environment     string `json:"environment,omitempty"`
+}
+
+// GetProfiles returns a summary of every profile, in ListProfiles order
+func GetProfiles() []ProfileInfo {
+	current := CurrentProfile()
+
+	var profiles []ProfileInfo
+	for _, name := range ListProfiles() {
+		get := func(key string) string {
+			return viper.GetString(ProfileKey(name, key))
+		}
+		info := ProfileInfo{
+			Name:      name,
+			Current:   name == current,
+			LoggedIn:  get("stored_at") != "" || get("api_key") != "",
+			UserEmail: get("user_email"),
+			BaseURL:   get("base_url"),
+		}
+		// The store setting is kept after logout so the next login reuses it
+		if info.LoggedIn {
+			info.CredentialStore = get("credential_store")
+		}
+		// The default profile shares its environment key with --env
+		if name != DefaultProfile {
+			info.Environment = get("environment")
+		}
+		profiles = append(profiles, info)
+	}
+	return profiles
+}
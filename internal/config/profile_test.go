@@ -0,0 +1,206 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// setupConfig starts from a fresh viper and package state, with the config
+// file in a temp dir holding content, and returns the file's path
+func setupConfig(t *testing.T, content string) string {
+	t.Helper()
+	reset := func() {
+		viper.Reset()
+		selectedProfile = ""
+		deletedProfiles = make(map[string]bool)
+		changes = make(map[string]interface{})
+	}
+	reset()
+	t.Cleanup(reset)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if content != "" {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Initialize(path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// readSaved reads the config file back with a separate viper
+func readSaved(t *testing.T, path string) *viper.Viper {
+	t.Helper()
+	saved := viper.New()
+	saved.SetConfigFile(path)
+	if err := saved.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	return saved
+}
+
+func TestProfileKey(t *testing.T) {
+	tests := []struct {
+		profile, key, want string
+	}{
+		{DefaultProfile, "api_key", "api_key"},
+		{"work", "api_key", "profiles.work.api_key"},
+		{"ci-2", "base_url", "profiles.ci-2.base_url"},
+	}
+	for _, tt := range tests {
+		if got := ProfileKey(tt.profile, tt.key); got != tt.want {
+			t.Errorf("ProfileKey(%q, %q) = %q, want %q", tt.profile, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestProfileSettings(t *testing.T) {
+	setupConfig(t, "user_email: default@example.com\nprofiles:\n  work:\n    user_email: work@example.com\n")
+
+	if got := GetProfileString("user_email"); got != "default@example.com" {
+		t.Errorf("default profile user_email = %q", got)
+	}
+
+	if err := UseProfile(" Work "); err != nil {
+		t.Fatal(err)
+	}
+	if got := ActiveProfile(); got != "work" {
+		t.Errorf("ActiveProfile() = %q, want work", got)
+	}
+	if got := GetProfileString("user_email"); got != "work@example.com" {
+		t.Errorf("work profile user_email = %q", got)
+	}
+	SetProfileString("key_name", "laptop")
+	if got := viper.GetString("profiles.work.key_name"); got != "laptop" {
+		t.Errorf("SetProfileString() stored %q under the work profile", got)
+	}
+	if got := viper.GetString("key_name"); got != "" {
+		t.Errorf("SetProfileString() changed the default profile: %q", got)
+	}
+
+	if err := UseProfile("not valid"); err == nil {
+		t.Error("UseProfile() accepted an invalid name")
+	}
+}
+
+func TestListProfilesAfterDelete(t *testing.T) {
+	path := setupConfig(t, `current_profile: staging
+profiles:
+  work:
+    user_email: work@example.com
+  staging:
+    user_email: staging@example.com
+  ci:
+    user_email: ci@example.com
+`)
+
+	if got, want := ListProfiles(), []string{"default", "ci", "staging", "work"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("ListProfiles() = %v, want %v", got, want)
+	}
+
+	DeleteProfile("staging")
+	DeleteProfile(DefaultProfile) // the default profile cannot be removed
+
+	if got, want := ListProfiles(), []string{"default", "ci", "work"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListProfiles() after DeleteProfile = %v, want %v", got, want)
+	}
+	if ProfileExists("staging") {
+		t.Error("ProfileExists(staging) after DeleteProfile")
+	}
+	if got := CurrentProfile(); got != DefaultProfile {
+		t.Errorf("CurrentProfile() = %q after deleting the current profile, want %q", got, DefaultProfile)
+	}
+
+	if err := SaveConfig(); err != nil {
+		t.Fatal(err)
+	}
+	saved := readSaved(t, path)
+	if saved.IsSet("profiles.staging") {
+		t.Error("deleted profile was saved")
+	}
+	if got := saved.GetString("profiles.ci.user_email"); got != "ci@example.com" {
+		t.Errorf("saved ci profile user_email = %q", got)
+	}
+	if got := saved.GetString("current_profile"); got != "" {
+		t.Errorf("saved current_profile = %q, want it cleared", got)
+	}
+}
+
+func TestSaveConfigWritesOnlyFileAndChanges(t *testing.T) {
+	path := setupConfig(t, "environment: prod\noutput: table\nprofiles:\n  work:\n    base_url: https://work.example.com\n")
+
+	// --env staging and environment variables picked up by AutomaticEnv
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("env", "", "")
+	flags.String("api-url", "", "")
+	if err := flags.Parse([]string{"--env", "staging", "--api-url", "http://localhost:3000"}); err != nil {
+		t.Fatal(err)
+	}
+	viper.BindPFlag("environment", flags.Lookup("env"))
+	viper.BindPFlag("api_url", flags.Lookup("api-url"))
+	t.Setenv("OUTPUT", "json")
+	t.Setenv("USER_EMAIL", "env@example.com")
+
+	if got := viper.GetString("environment"); got != "staging" {
+		t.Fatalf("environment for this run = %q, want staging", got)
+	}
+	if got := GetProfileString("output"); got != "json" {
+		t.Fatalf("output for this run = %q, want the environment's json", got)
+	}
+
+	SetProfileString("key_name", "laptop")
+	if err := SaveConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := readSaved(t, path)
+	want := map[string]string{
+		"environment":            "prod",
+		"output":                 "table",
+		"key_name":               "laptop",
+		"profiles.work.base_url": "https://work.example.com",
+		"api_url":                "",
+		"user_email":             "",
+	}
+	for key, value := range want {
+		if got := saved.GetString(key); got != value {
+			t.Errorf("saved %s = %q, want %q", key, got, value)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"staging", "localhost", "json", "env@example.com"} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("config file contains %q from flags or the environment:\n%s", leaked, data)
+		}
+	}
+}
+
+func TestSaveConfigCreatesFile(t *testing.T) {
+	path := setupConfig(t, "")
+
+	SetCurrentProfile("work")
+	SetString("profiles.work.user_email", "work@example.com")
+	if err := SaveConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := readSaved(t, path)
+	if got := saved.GetString("current_profile"); got != "work" {
+		t.Errorf("saved current_profile = %q, want work", got)
+	}
+	if got := saved.GetString("profiles.work.user_email"); got != "work@example.com" {
+		t.Errorf("saved work user_email = %q", got)
+	}
+}
@@ -8,12 +8,31 @@ import (
 	"time"
 
 	"github.com/ddod/leanmcp-cli/internal/api"
+	"github.com/ddod/leanmcp-cli/internal/filesystem"
 )
 
 // ProjectConfig represents the local project configuration
 type ProjectConfig struct {
-	Project ProjectInfo `json:"project"`
-	CLI     CLIInfo     `json:"cli"`
+	Project ProjectInfo    `json:"project"`
+	CLI     CLIInfo        `json:"cli"`
+	Files   *FilesConfig   `json:"files,omitempty"`
+	Secrets *SecretsConfig `json:"secrets,omitempty"`
+}
+
+// FilesConfig overrides which files are uploaded. Patterns use .gitignore
+// syntax; include wins over every other rule, including the built-in excludes.
+type FilesConfig struct {
+	Include  []string `json:"include,omitempty"`
+	Exclude  []string `json:"exclude,omitempty"`
+	Symlinks string   `json:"symlinks,omitempty"` // follow, preserve or skip
+}
+
+// SecretsConfig allows findings of the pre-upload secret scan. allowPaths are
+// .gitignore-style patterns of files that are not scanned; allowPatterns are
+// regular expressions matched against the detected value.
+type SecretsConfig struct {
+	AllowPaths    []string `json:"allowPaths,omitempty"`
+	AllowPatterns []string `json:"allowPatterns,omitempty"`
 }
 
 // ProjectInfo contains project details from the API
@@ -25,6 +44,7 @@ type ProjectInfo struct {
 	Status        string `json:"status"`
 	S3Location    string `json:"s3Location"`
 	RepositoryURL string `json:"repositoryUrl"`
+	ContentHash   string `json:"contentHash,omitempty"` // manifest checksum of the last upload
 	CreatedAt     string `json:"createdAt"`
 	UpdatedAt     string `json:"updatedAt"`
 }
@@ -66,6 +86,12 @@ func SaveProjectConfig(projectPath string, project *api.Project) error {
 		},
 	}
 	
+	// Keep file patterns and secret allowlists from an existing config
+	if existing, err := LoadProjectConfig(projectPath); err == nil {
+		config.Files = existing.Files
+		config.Secrets = existing.Secrets
+	}
+	
 	// Save to file
 	configPath := filepath.Join(leanmcpDir, "config.json")
 	data, err := json.MarshalIndent(config, "", "  ")
@@ -102,6 +128,57 @@ func LoadProjectConfig(projectPath string) (*ProjectConfig, error) {
 	return &config, nil
 }
 
+// ProjectScanOptions returns the include and exclude patterns and the symlink
+// policy from the files section of .leanmcp/config.json. A directory without
+// a config has none.
+func ProjectScanOptions(projectPath string) (filesystem.ScanOptions, error) {
+	var options filesystem.ScanOptions
+	if !HasProjectConfig(projectPath) {
+		return options, nil
+	}
+	
+	config, err := LoadProjectConfig(projectPath)
+	if err != nil {
+		return options, err
+	}
+	
+	if config.Files != nil {
+		source := filepath.ToSlash(filepath.Join(".leanmcp", "config.json"))
+		options.Include = filesystem.NewPatterns(source, config.Files.Include)
+		options.Exclude = filesystem.NewPatterns(source, config.Files.Exclude)
+		
+		if config.Files.Symlinks != "" {
+			options.Symlinks, err = filesystem.ParseSymlinkPolicy(config.Files.Symlinks)
+			if err != nil {
+				return options, fmt.Errorf("%s: %w", source, err)
+			}
+		}
+	}
+	
+	return options, nil
+}
+
+// ProjectSecretScanOptions returns the secret scan allowlist from the secrets
+// section of .leanmcp/config.json. A directory without a config has none.
+func ProjectSecretScanOptions(projectPath string) (filesystem.SecretScanOptions, error) {
+	var options filesystem.SecretScanOptions
+	if !HasProjectConfig(projectPath) {
+		return options, nil
+	}
+	
+	config, err := LoadProjectConfig(projectPath)
+	if err != nil {
+		return options, err
+	}
+	
+	if config.Secrets != nil {
+		options.AllowPaths = config.Secrets.AllowPaths
+		options.AllowPatterns = config.Secrets.AllowPatterns
+	}
+	
+	return options, nil
+}
+
 // HasProjectConfig checks if .leanmcp/config.json exists in the current directory
 func HasProjectConfig(projectPath string) bool {
 	configPath := filepath.Join(projectPath, ".leanmcp", "config.json")
@@ -138,6 +215,9 @@ func UpdateProjectConfig(projectPath string, updates ProjectInfo) error {
 	if updates.RepositoryURL != "" {
 		config.Project.RepositoryURL = updates.RepositoryURL
 	}
+	if updates.ContentHash != "" {
+		config.Project.ContentHash = updates.ContentHash
+	}
 	
 	// Update last sync time
 	config.CLI.LastSync = time.Now().Format(time.RFC3339)
@@ -0,0 +1,65 @@
+package display
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ddod/leanmcp-cli/internal/filesystem"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+)
+
+// FilesTable displays the files that would be uploaded and their total size
+func FilesTable(files []filesystem.FileInfo, stats filesystem.FileStats) {
+	if len(files) == 0 {
+		fmt.Println("No files found (directory might be empty or all files are ignored).")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Path", "Size", "Mode"})
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	for _, file := range files {
+		path := file.RelPath
+		if file.IsSymlink() {
+			path += " -> " + file.LinkTarget
+		}
+		table.Append([]string{
+			path,
+			filesystem.GetHumanReadableSize(file.Size),
+			file.Mode.String(),
+		})
+	}
+
+	table.Render()
+
+	fmt.Printf("\n%s %d files, %s\n", color.CyanString("Total:"),
+		stats.TotalFiles, filesystem.GetHumanReadableSize(stats.TotalSize))
+}
+
+// PrintIgnoreResult explains whether a path is uploaded and which rule decided it
+func PrintIgnoreResult(result *filesystem.IgnoreResult) {
+	name := result.Path
+	if result.IsDir {
+		name += "/"
+	}
+
+	if !result.Ignored {
+		fmt.Printf("✅ %s %s\n", name, color.GreenString("is uploaded"))
+		if result.Rule != nil {
+			fmt.Printf("   %s %s\n", color.CyanString("Re-included by:"), result.Rule)
+		}
+		return
+	}
+
+	fmt.Printf("❌ %s %s\n", name, color.RedString("is ignored"))
+	if result.Parent != "" {
+		fmt.Printf("   %s %s/\n", color.CyanString("Inside ignored directory:"), result.Parent)
+	}
+	if result.Rule != nil {
+		fmt.Printf("   %s %s\n", color.CyanString("Excluded by:"), result.Rule)
+	}
+}
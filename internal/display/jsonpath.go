@@ -0,0 +1,641 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPath is a compiled kubectl-style JSONPath template such as
+// '{.items[*].id}' or '{range .items[*]}{.id}{"\t"}{.status}{"\n"}{end}'.
+//
+// Supported syntax: .field, ['field'], [n], [-n], [start:end], [*], [a,b],
+// ..field (recursive descent), [?(@.field == "value")] filters with
+// ==, !=, <, <=, >, >=, string literals in braces and range/end blocks.
+type JSONPath struct {
+	nodes []jsonPathNode
+}
+
+// jsonPathNode is a piece of a parsed template
+type jsonPathNode struct {
+	text    string         // literal text, when steps and body are nil
+	steps   []jsonPathStep // expression to evaluate
+	body    []jsonPathNode // range body, evaluated once per result of steps
+	isRange bool
+}
+
+// jsonPathStep is one step of an expression
+type jsonPathStep struct {
+	kind    stepKind
+	names   []string
+	indexes []int
+	start   *int
+	end     *int
+	filter  *jsonPathFilter
+}
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepWildcard
+	stepRecursive
+	stepIndex
+	stepSlice
+	stepFilter
+)
+
+// jsonPathFilter is a [?(...)] predicate
+type jsonPathFilter struct {
+	left     []jsonPathStep
+	operator string // empty for an existence check
+	right    interface{}
+}
+
+// ParseJSONPath compiles a JSONPath template
+func ParseJSONPath(template string) (*JSONPath, error) {
+	nodes, rest, err := parseJSONPathNodes(template, false)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("jsonpath: unexpected {end}")
+	}
+	return &JSONPath{nodes: nodes}, nil
+}
+
+// parseJSONPathNodes parses until the end of input or, inside a range, until
+// the matching {end}. It returns the unparsed remainder after {end}.
+func parseJSONPathNodes(template string, inRange bool) ([]jsonPathNode, string, error) {
+	var nodes []jsonPathNode
+
+	for template != "" {
+		open := strings.IndexByte(template, '{')
+		if open < 0 {
+			nodes = append(nodes, jsonPathNode{text: template})
+			template = ""
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jsonPathNode{text: template[:open]})
+		}
+
+		close, err := findClosingBrace(template, open)
+		if err != nil {
+			return nil, "", err
+		}
+		action := strings.TrimSpace(template[open+1 : close])
+		template = template[close+1:]
+
+		switch {
+		case action == "end":
+			if !inRange {
+				return nodes, "{end}", nil
+			}
+			return nodes, template, nil
+		case strings.HasPrefix(action, "range "):
+			steps, err := parseJSONPathExpr(strings.TrimSpace(strings.TrimPrefix(action, "range ")))
+			if err != nil {
+				return nil, "", err
+			}
+			body, rest, err := parseJSONPathNodes(template, true)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{steps: steps, body: body, isRange: true})
+			template = rest
+		case strings.HasPrefix(action, `"`):
+			text, err := strconv.Unquote(action)
+			if err != nil {
+				return nil, "", fmt.Errorf("jsonpath: invalid string literal %s", action)
+			}
+			nodes = append(nodes, jsonPathNode{text: text})
+		default:
+			steps, err := parseJSONPathExpr(action)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{steps: steps})
+		}
+	}
+
+	if inRange {
+		return nil, "", fmt.Errorf("jsonpath: range without matching {end}")
+	}
+	return nodes, "", nil
+}
+
+// findClosingBrace finds the brace that closes the action opened at open,
+// skipping braces inside quoted strings
+func findClosingBrace(template string, open int) (int, error) {
+	var quote byte
+	for i := open + 1; i < len(template); i++ {
+		c := template[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("jsonpath: unclosed action in %q", template[open:])
+}
+
+// parseJSONPathExpr parses an expression like .items[*].id
+func parseJSONPathExpr(expr string) ([]jsonPathStep, error) {
+	expr = strings.TrimPrefix(expr, "$")
+	expr = strings.TrimPrefix(expr, "@")
+
+	// Non-nil even when empty, so {.} and {@} select the current value
+	steps := []jsonPathStep{}
+	for expr != "" {
+		switch {
+		case strings.HasPrefix(expr, ".."):
+			steps = append(steps, jsonPathStep{kind: stepRecursive})
+			expr = expr[2:]
+			// A bare name may follow ..
+			if expr != "" && expr[0] != '[' {
+				name, rest := readJSONPathName(expr)
+				steps = append(steps, nameStep(name))
+				expr = rest
+			}
+		case expr[0] == '.':
+			name, rest := readJSONPathName(expr[1:])
+			if name != "" {
+				steps = append(steps, nameStep(name))
+			}
+			expr = rest
+		case expr[0] == '[':
+			end := findClosingBracket(expr)
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath: unclosed [ in %q", expr)
+			}
+			step, err := parseJSONPathBracket(strings.TrimSpace(expr[1:end]))
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+			expr = expr[end+1:]
+		default:
+			name, rest := readJSONPathName(expr)
+			if name == "" {
+				return nil, fmt.Errorf("jsonpath: unexpected %q", expr)
+			}
+			steps = append(steps, nameStep(name))
+			expr = rest
+		}
+	}
+
+	return steps, nil
+}
+
+// nameStep builds a field step, treating * as a wildcard
+func nameStep(name string) jsonPathStep {
+	if name == "*" {
+		return jsonPathStep{kind: stepWildcard}
+	}
+	return jsonPathStep{kind: stepField, names: []string{name}}
+}
+
+// readJSONPathName reads a dotted field name up to the next . or [
+func readJSONPathName(expr string) (string, string) {
+	i := strings.IndexAny(expr, ".[")
+	if i < 0 {
+		return expr, ""
+	}
+	return expr[:i], expr[i:]
+}
+
+// findClosingBracket finds the ] matching the [ at the start of expr
+func findClosingBracket(expr string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseJSONPathBracket parses the contents of [...]
+func parseJSONPathBracket(content string) (jsonPathStep, error) {
+	switch {
+	case content == "*":
+		return jsonPathStep{kind: stepWildcard}, nil
+	case strings.HasPrefix(content, "?(") && strings.HasSuffix(content, ")"):
+		filter, err := parseJSONPathFilter(content[2 : len(content)-1])
+		if err != nil {
+			return jsonPathStep{}, err
+		}
+		return jsonPathStep{kind: stepFilter, filter: filter}, nil
+	case strings.HasPrefix(content, "'") || strings.HasPrefix(content, `"`):
+		var names []string
+		for _, part := range strings.Split(content, ",") {
+			part = strings.TrimSpace(part)
+			if len(part) < 2 {
+				return jsonPathStep{}, fmt.Errorf("jsonpath: invalid field %q", part)
+			}
+			names = append(names, part[1:len(part)-1])
+		}
+		return jsonPathStep{kind: stepField, names: names}, nil
+	case strings.Contains(content, ":"):
+		parts := strings.SplitN(content, ":", 2)
+		step := jsonPathStep{kind: stepSlice}
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return jsonPathStep{}, fmt.Errorf("jsonpath: invalid slice %q", content)
+			}
+			if i == 0 {
+				step.start = &n
+			} else {
+				step.end = &n
+			}
+		}
+		return step, nil
+	default:
+		step := jsonPathStep{kind: stepIndex}
+		for _, part := range strings.Split(content, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				return jsonPathStep{}, fmt.Errorf("jsonpath: invalid index %q", content)
+			}
+			step.indexes = append(step.indexes, n)
+		}
+		return step, nil
+	}
+}
+
+// jsonPathOperators are checked longest first so <= is not read as <
+var jsonPathOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// findJSONPathOperator returns the position and text of the first comparison
+// operator in content that is not inside a quoted string
+func findJSONPathOperator(content string) (int, string) {
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		default:
+			for _, op := range jsonPathOperators {
+				if strings.HasPrefix(content[i:], op) {
+					return i, op
+				}
+			}
+		}
+	}
+	return -1, ""
+}
+
+// parseJSONPathFilter parses a predicate like @.status == "live"
+func parseJSONPathFilter(content string) (*jsonPathFilter, error) {
+	content = strings.TrimSpace(content)
+
+	if i, op := findJSONPathOperator(content); i >= 0 {
+		left, err := parseJSONPathExpr(strings.TrimSpace(content[:i]))
+		if err != nil {
+			return nil, err
+		}
+
+		rightText := strings.TrimSpace(content[i+len(op):])
+		var right interface{}
+		switch {
+		case strings.HasPrefix(rightText, `"`):
+			text, err := strconv.Unquote(rightText)
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath: invalid filter value %s", rightText)
+			}
+			right = text
+		case strings.HasPrefix(rightText, "'"):
+			if len(rightText) < 2 || !strings.HasSuffix(rightText, "'") {
+				return nil, fmt.Errorf("jsonpath: invalid filter value %s", rightText)
+			}
+			right = rightText[1 : len(rightText)-1]
+		case rightText == "true" || rightText == "false":
+			right = rightText == "true"
+		default:
+			n, err := strconv.ParseFloat(rightText, 64)
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath: invalid filter value %q", rightText)
+			}
+			right = n
+		}
+
+		return &jsonPathFilter{left: left, operator: op, right: right}, nil
+	}
+
+	left, err := parseJSONPathExpr(content)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonPathFilter{left: left}, nil
+}
+
+// Execute evaluates the template against data and writes the result
+func (jp *JSONPath) Execute(w io.Writer, data interface{}) error {
+	var buf bytes.Buffer
+	if err := executeJSONPathNodes(&buf, jp.nodes, data); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// executeJSONPathNodes renders nodes with data as the current context
+func executeJSONPathNodes(buf *bytes.Buffer, nodes []jsonPathNode, data interface{}) error {
+	for _, node := range nodes {
+		switch {
+		case node.isRange:
+			for _, item := range evaluateJSONPath(node.steps, []interface{}{data}) {
+				if err := executeJSONPathNodes(buf, node.body, item); err != nil {
+					return err
+				}
+			}
+		case node.steps != nil:
+			results := evaluateJSONPath(node.steps, []interface{}{data})
+			for i, result := range results {
+				if i > 0 {
+					buf.WriteByte(' ')
+				}
+				if err := writeJSONPathValue(buf, result); err != nil {
+					return err
+				}
+			}
+		default:
+			buf.WriteString(node.text)
+		}
+	}
+	return nil
+}
+
+// writeJSONPathValue prints strings and numbers as-is, other values as JSON
+func writeJSONPathValue(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		buf.WriteString(v)
+	case json.Number:
+		buf.WriteString(v.String())
+	case nil:
+		// Missing values print nothing
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
+
+// evaluateJSONPath applies steps to every value in current
+func evaluateJSONPath(steps []jsonPathStep, current []interface{}) []interface{} {
+	for _, step := range steps {
+		var next []interface{}
+		for _, value := range current {
+			next = append(next, applyJSONPathStep(step, value)...)
+		}
+		current = next
+	}
+	return current
+}
+
+// applyJSONPathStep applies a single step to a value. Missing fields and
+// out-of-range indexes yield no results rather than an error.
+func applyJSONPathStep(step jsonPathStep, value interface{}) []interface{} {
+	switch step.kind {
+	case stepField:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		var results []interface{}
+		for _, name := range step.names {
+			if field, ok := object[name]; ok {
+				results = append(results, field)
+			}
+		}
+		return results
+
+	case stepWildcard:
+		switch v := value.(type) {
+		case []interface{}:
+			return v
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			results := make([]interface{}, 0, len(keys))
+			for _, key := range keys {
+				results = append(results, v[key])
+			}
+			return results
+		}
+		return nil
+
+	case stepRecursive:
+		return collectJSONPathDescendants(value, nil)
+
+	case stepIndex:
+		array, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		var results []interface{}
+		for _, index := range step.indexes {
+			if index < 0 {
+				index += len(array)
+			}
+			if index >= 0 && index < len(array) {
+				results = append(results, array[index])
+			}
+		}
+		return results
+
+	case stepSlice:
+		array, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		start, end := 0, len(array)
+		if step.start != nil {
+			start = clampJSONPathIndex(*step.start, len(array))
+		}
+		if step.end != nil {
+			end = clampJSONPathIndex(*step.end, len(array))
+		}
+		if start >= end {
+			return nil
+		}
+		return array[start:end]
+
+	case stepFilter:
+		array, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		var results []interface{}
+		for _, item := range array {
+			if step.filter.matches(item) {
+				results = append(results, item)
+			}
+		}
+		return results
+	}
+
+	return nil
+}
+
+// clampJSONPathIndex resolves a possibly negative slice bound
+func clampJSONPathIndex(index, length int) int {
+	if index < 0 {
+		index += length
+	}
+	if index < 0 {
+		return 0
+	}
+	if index > length {
+		return length
+	}
+	return index
+}
+
+// collectJSONPathDescendants returns value and everything nested in it
+func collectJSONPathDescendants(value interface{}, results []interface{}) []interface{} {
+	results = append(results, value)
+
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			results = collectJSONPathDescendants(item, results)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			results = collectJSONPathDescendants(v[key], results)
+		}
+	}
+
+	return results
+}
+
+// matches evaluates the filter against an array element
+func (f *jsonPathFilter) matches(item interface{}) bool {
+	results := evaluateJSONPath(f.left, []interface{}{item})
+	if f.operator == "" {
+		return len(results) > 0
+	}
+
+	for _, result := range results {
+		if compareJSONPathValues(result, f.operator, f.right) {
+			return true
+		}
+	}
+	return false
+}
+
+// compareJSONPathValues compares a document value with a filter literal
+func compareJSONPathValues(left interface{}, operator string, right interface{}) bool {
+	switch r := right.(type) {
+	case float64:
+		var l float64
+		switch v := left.(type) {
+		case json.Number:
+			f, err := v.Float64()
+			if err != nil {
+				return false
+			}
+			l = f
+		default:
+			return false
+		}
+		switch operator {
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		case ">=":
+			return l >= r
+		}
+	case bool:
+		l, ok := left.(bool)
+		if !ok {
+			return operator == "!="
+		}
+		switch operator {
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		}
+	case string:
+		l, ok := left.(string)
+		if !ok {
+			return operator == "!="
+		}
+		switch operator {
+		case "==":
+			return l == r
+		case "!=":
+			return l != r
+		case "<":
+			return l < r
+		case "<=":
+			return l <= r
+		case ">":
+			return l > r
+		case ">=":
+			return l >= r
+		}
+	}
+	return false
+}
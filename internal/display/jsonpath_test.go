@@ -0,0 +1,130 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const jsonPathDocument = `{
+	"kind": "List",
+	"items": [
+		{"id": "p1", "name": "api", "status": "live", "replicas": 3, "public": true, "tags": ["a", "b"]},
+		{"id": "p2", "name": "a<b", "status": "failed", "replicas": 0, "public": false},
+		{"id": "p3", "name": "x==y", "status": "live", "replicas": 10, "meta": {"id": "m3"}}
+	],
+	"owner": {"id": "u1", "email": "dev@example.com"}
+}`
+
+// decodeJSONPathDocument decodes a document the way Printer does
+func decodeJSONPathDocument(t *testing.T, document string) interface{} {
+	t.Helper()
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestJSONPathExecute(t *testing.T) {
+	data := decodeJSONPathDocument(t, jsonPathDocument)
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		// Paths
+		{"field", "{.kind}", "List"},
+		{"dollar root", "{$.kind}", "List"},
+		{"nested field", "{.owner.email}", "dev@example.com"},
+		{"bracket field", "{['owner']['id']}", "u1"},
+		{"several fields", "{.owner['id','email']}", "u1 dev@example.com"},
+		{"missing field", "{.nothing.here}", ""},
+		{"wildcard", "{.items[*].id}", "p1 p2 p3"},
+		{"object wildcard", "{.owner.*}", "dev@example.com u1"},
+		{"index", "{.items[1].id}", "p2"},
+		{"negative index", "{.items[-1].id}", "p3"},
+		{"several indexes", "{.items[0,2].id}", "p1 p3"},
+		{"index out of range", "{.items[5].id}", ""},
+		{"number", "{.items[2].replicas}", "10"},
+		{"bool", "{.items[0].public}", "true"},
+		{"array value", "{.items[0].tags}", `["a","b"]`},
+		{"current value", "{range .items[0].tags[*]}<{@}>{end}", "<a><b>"},
+
+		// Slices
+		{"slice", "{.items[0:2].id}", "p1 p2"},
+		{"open start", "{.items[:1].id}", "p1"},
+		{"open end", "{.items[1:].id}", "p2 p3"},
+		{"negative start", "{.items[-2:].id}", "p2 p3"},
+		{"clamped end", "{.items[1:10].id}", "p2 p3"},
+		{"empty slice", "{.items[2:1].id}", ""},
+
+		// Recursive descent
+		{"recursive field", "{..email}", "dev@example.com"},
+		{"recursive ids", "{.items..id}", "p1 p2 p3 m3"},
+		{"recursive wildcard index", "{..tags[0]}", "a"},
+
+		// Filters
+		{"string equal", `{.items[?(@.status=="live")].id}`, "p1 p3"},
+		{"single quotes", `{.items[?(@.status == 'failed')].id}`, "p2"},
+		{"not equal", `{.items[?(@.status != "live")].id}`, "p2"},
+		{"number compare", "{.items[?(@.replicas > 2)].id}", "p1 p3"},
+		{"number less or equal", "{.items[?(@.replicas <= 3)].id}", "p1 p2"},
+		{"bool", "{.items[?(@.public == true)].id}", "p1"},
+		{"existence", "{.items[?(@.meta)].id}", "p3"},
+		{"quoted less than", `{.items[?(@.name=="a<b")].id}`, "p2"},
+		{"quoted equals", `{.items[?(@.name == "x==y")].id}`, "p3"},
+		{"quoted operator not equal", `{.items[?(@.name != "a<b")].id}`, "p1 p3"},
+		{"escaped quote", `{.items[?(@.name == "a\"b")].id}`, ""},
+		{"missing field never matches", `{.items[?(@.meta.id != "x")].id}`, "p3"},
+
+		// Templates
+		{"literal text", `{.kind}{"\t"}{.owner.id}{"\n"}`, "List\tu1\n"},
+		{"range", `{range .items[*]}{.id}={.status}{"\n"}{end}`, "p1=live\np2=failed\np3=live\n"},
+		{"range with filter", `{range .items[?(@.replicas >= 3)]}[{.name}]{end}`, "[api][x==y]"},
+		{"braces in literal", `{"{"}{.kind}{"}"}`, "{List}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jp, err := ParseJSONPath(tt.template)
+			if err != nil {
+				t.Fatalf("ParseJSONPath(%q) error = %v", tt.template, err)
+			}
+			var buf bytes.Buffer
+			if err := jp.Execute(&buf, data); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONPathErrors(t *testing.T) {
+	tests := []string{
+		"{.items",
+		"{.items[0}",
+		"{range .items[*]}{.id}",
+		"{end}",
+		`{"unterminated}`,
+		"{.items[x]}",
+		"{.items[1:x]}",
+		`{.items[?(@.name == "a)]}`,
+		"{.items[?(@.name == 'a)]}",
+		"{.items[?(@.replicas > many)]}",
+	}
+
+	for _, template := range tests {
+		t.Run(template, func(t *testing.T) {
+			if _, err := ParseJSONPath(template); err == nil {
+				t.Errorf("ParseJSONPath(%q) succeeded, want an error", template)
+			}
+		})
+	}
+}
@@ -0,0 +1,462 @@
+package display
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format selectable with --output
+type Format string
+
+// Supported output formats
+const (
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+	FormatCSV   Format = "csv"
+
+	// FormatTemplate executes a Go text/template against each result
+	FormatTemplate Format = "template"
+	// FormatJSONPath evaluates a kubectl-style JSONPath template
+	FormatJSONPath Format = "jsonpath"
+)
+
+// Printer renders API results in the selected output format. Field names in
+// JSON, YAML and CSV output are the JSON names of the api types.
+type Printer struct {
+	Format Format
+	Out    io.Writer
+
+	template *template.Template
+	jsonPath *JSONPath
+}
+
+// NewPrinter creates a printer for an --output value. An empty value selects
+// the table format. Templates are given as template=<go-template> (alias
+// go-template=) and jsonpath=<template>.
+func NewPrinter(spec string) (*Printer, error) {
+	name, arg, hasArg := strings.Cut(spec, "=")
+	format := Format(strings.ToLower(strings.TrimSpace(name)))
+	if format == "go-template" {
+		format = FormatTemplate
+	}
+
+	printer := &Printer{Format: format, Out: os.Stdout}
+
+	switch format {
+	case "":
+		printer.Format = FormatTable
+	case FormatTable, FormatJSON, FormatYAML, FormatCSV:
+		if hasArg {
+			return nil, fmt.Errorf("output format %q does not take an argument", name)
+		}
+	case FormatTemplate:
+		if arg == "" {
+			return nil, fmt.Errorf("template output requires a template, e.g. -o template='{{.ID}}'")
+		}
+		tmpl, err := template.New("output").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %w", err)
+		}
+		printer.template = tmpl
+	case FormatJSONPath:
+		if arg == "" {
+			return nil, fmt.Errorf("jsonpath output requires an expression, e.g. -o jsonpath='{.items[*].id}'")
+		}
+		jp, err := ParseJSONPath(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jsonpath: %w", err)
+		}
+		printer.jsonPath = jp
+	default:
+		return nil, fmt.Errorf("unsupported output format %q (use table, json, yaml, csv, template=... or jsonpath=...)", name)
+	}
+
+	return printer, nil
+}
+
+// IsTable reports whether output is human-readable rather than machine-readable
+func (p *Printer) IsTable() bool {
+	return p.Format == FormatTable
+}
+
+// Print renders v. For the table format the table function is called to draw
+// the colored, human-readable view.
+func (p *Printer) Print(v interface{}, table func()) error {
+	switch p.Format {
+	case FormatJSON:
+		return p.printJSON(v)
+	case FormatYAML:
+		return p.printYAML(v)
+	case FormatCSV:
+		return p.printCSV(v)
+	case FormatTemplate:
+		return p.printTemplate(v)
+	case FormatJSONPath:
+		return p.printJSONPath(v)
+	default:
+		table()
+		return nil
+	}
+}
+
+// printJSON writes v as indented JSON
+func (p *Printer) printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	_, err = fmt.Fprintln(p.Out, string(data))
+	return err
+}
+
+// printYAML writes v as YAML, keeping the field order of the JSON encoding
+func (p *Printer) printYAML(v interface{}) error {
+	value, err := toOrdered(v)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(p.Out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value.yamlNode()); err != nil {
+		return fmt.Errorf("failed to encode YAML: %w", err)
+	}
+
+	return encoder.Close()
+}
+
+// printTemplate executes the Go template once per item of a list, or once for
+// a single result. Fields use the Go names of the api types ({{.ID}}).
+// A newline is added after each item unless the template ends with one.
+func (p *Printer) printTemplate(v interface{}) error {
+	items := []interface{}{v}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		items = make([]interface{}, value.Len())
+		for i := range items {
+			items[i] = value.Index(i).Interface()
+		}
+	}
+
+	for _, item := range items {
+		var buf bytes.Buffer
+		if err := p.template.Execute(&buf, item); err != nil {
+			return fmt.Errorf("failed to execute template: %w", err)
+		}
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := p.Out.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printJSONPath evaluates the JSONPath template against the JSON form of v.
+// Lists are wrapped as {"items": [...]} so expressions read like kubectl's.
+func (p *Printer) printJSONPath(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return fmt.Errorf("failed to decode output: %w", err)
+	}
+	if list, ok := document.([]interface{}); ok {
+		document = map[string]interface{}{"items": list}
+	}
+
+	var buf bytes.Buffer
+	if err := p.jsonPath.Execute(&buf, document); err != nil {
+		return err
+	}
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+
+	_, err = p.Out.Write(buf.Bytes())
+	return err
+}
+
+// printCSV writes v as CSV with one row per item. Columns are the union of the
+// items' fields in first-seen order; nested values are written as JSON.
+func (p *Printer) printCSV(v interface{}) error {
+	value, err := toOrdered(v)
+	if err != nil {
+		return err
+	}
+
+	var rows []*orderedValue
+	switch value.kind {
+	case kindArray:
+		rows = value.items
+	default:
+		rows = []*orderedValue{value}
+	}
+
+	var header []string
+	if len(rows) == 0 {
+		// No items to learn columns from, so use the element type's fields
+		header = jsonFieldNames(reflect.TypeOf(v))
+	}
+
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		if row.kind != kindObject {
+			header = []string{"value"}
+			break
+		}
+		for _, key := range row.keys {
+			if !seen[key] {
+				seen[key] = true
+				header = append(header, key)
+			}
+		}
+	}
+
+	writer := csv.NewWriter(p.Out)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(header))
+		if row.kind != kindObject {
+			record[0] = row.csvCell()
+		} else {
+			for i, key := range header {
+				if field, ok := row.fields[key]; ok {
+					record[i] = field.csvCell()
+				}
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// jsonFieldNames returns the JSON field names of a struct type, looking
+// through pointers, slices and arrays to their element type
+func jsonFieldNames(t reflect.Type) []string {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return []string{"value"}
+	}
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag := field.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if tagName := strings.Split(tag, ",")[0]; tagName != "" {
+				name = tagName
+			}
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// valueKind identifies the JSON type of an orderedValue
+type valueKind int
+
+const (
+	kindScalar valueKind = iota
+	kindObject
+	kindArray
+)
+
+// orderedValue is a decoded JSON value that remembers object key order, so
+// YAML and CSV output follow the struct field order of the api types
+type orderedValue struct {
+	kind   valueKind
+	scalar interface{} // string, json.Number, bool or nil
+	keys   []string
+	fields map[string]*orderedValue
+	items  []*orderedValue
+}
+
+// toOrdered converts v to an orderedValue via its JSON encoding
+func toOrdered(v interface{}) (*orderedValue, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	return decodeOrdered(decoder)
+}
+
+// decodeOrdered reads one JSON value from the decoder's token stream
+func decodeOrdered(decoder *json.Decoder) (*orderedValue, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			value := &orderedValue{kind: kindObject, fields: make(map[string]*orderedValue)}
+			for decoder.More() {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := keyToken.(string)
+				field, err := decodeOrdered(decoder)
+				if err != nil {
+					return nil, err
+				}
+				value.keys = append(value.keys, key)
+				value.fields[key] = field
+			}
+			_, err := decoder.Token() // closing brace
+			return value, err
+		case '[':
+			value := &orderedValue{kind: kindArray, items: []*orderedValue{}}
+			for decoder.More() {
+				item, err := decodeOrdered(decoder)
+				if err != nil {
+					return nil, err
+				}
+				value.items = append(value.items, item)
+			}
+			_, err := decoder.Token() // closing bracket
+			return value, err
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", t)
+	default:
+		return &orderedValue{kind: kindScalar, scalar: t}, nil
+	}
+}
+
+// yamlNode converts the value to a YAML node tree
+func (v *orderedValue) yamlNode() *yaml.Node {
+	switch v.kind {
+	case kindObject:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range v.keys {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+				v.fields[key].yamlNode())
+		}
+		return node
+	case kindArray:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v.items {
+			node.Content = append(node.Content, item.yamlNode())
+		}
+		return node
+	}
+
+	switch s := v.scalar.(type) {
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(s)}
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(s.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: s.String()}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprint(s)}
+	}
+}
+
+// csvCell renders the value as a single CSV field. Lists of scalars are joined
+// with semicolons; objects and nested lists are written as compact JSON.
+func (v *orderedValue) csvCell() string {
+	switch v.kind {
+	case kindScalar:
+		if v.scalar == nil {
+			return ""
+		}
+		return fmt.Sprint(v.scalar)
+	case kindArray:
+		parts := make([]string, 0, len(v.items))
+		for _, item := range v.items {
+			if item.kind != kindScalar {
+				return v.compactJSON()
+			}
+			parts = append(parts, item.csvCell())
+		}
+		return strings.Join(parts, ";")
+	default:
+		return v.compactJSON()
+	}
+}
+
+// compactJSON re-encodes the value as single-line JSON
+func (v *orderedValue) compactJSON() string {
+	var buf bytes.Buffer
+	v.writeJSON(&buf)
+	return buf.String()
+}
+
+// writeJSON encodes the value preserving key order
+func (v *orderedValue) writeJSON(buf *bytes.Buffer) {
+	switch v.kind {
+	case kindObject:
+		buf.WriteByte('{')
+		for i, key := range v.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			keyData, _ := json.Marshal(key)
+			buf.Write(keyData)
+			buf.WriteByte(':')
+			v.fields[key].writeJSON(buf)
+		}
+		buf.WriteByte('}')
+	case kindArray:
+		buf.WriteByte('[')
+		for i, item := range v.items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			item.writeJSON(buf)
+		}
+		buf.WriteByte(']')
+	default:
+		data, _ := json.Marshal(v.scalar)
+		buf.Write(data)
+	}
+}
@@ -0,0 +1,175 @@
+package display
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// outputItem has fields whose JSON order differs from alphabetical order
+type outputItem struct {
+	Name     string            `json:"name"`
+	ID       string            `json:"id"`
+	Replicas int               `json:"replicas"`
+	Ratio    float64           `json:"ratio,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Owner    *outputOwner      `json:"owner,omitempty"`
+	Created  *time.Time        `json:"createdAt"`
+	internal string
+	Skipped  string `json:"-"`
+	Untagged bool
+}
+
+type outputOwner struct {
+	Email string `json:"email"`
+	ID    string `json:"id"`
+}
+
+// render prints v in format and returns the output
+func render(t *testing.T, format string, v interface{}) string {
+	t.Helper()
+	printer, err := NewPrinter(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	printer.Out = &buf
+	if err := printer.Print(v, func() { t.Error("table function called for", format) }); err != nil {
+		t.Fatalf("Print() error = %v", err)
+	}
+	return buf.String()
+}
+
+func TestPrintYAMLKeepsFieldOrder(t *testing.T) {
+	items := []outputItem{{
+		Name:     "api",
+		ID:       "p1",
+		Replicas: 3,
+		Ratio:    0.5,
+		Tags:     []string{"a", "b"},
+		Owner:    &outputOwner{Email: "dev@example.com", ID: "u1"},
+	}}
+
+	want := `- name: api
+  id: p1
+  replicas: 3
+  ratio: 0.5
+  tags:
+    - a
+    - b
+  owner:
+    email: dev@example.com
+    id: u1
+  createdAt: null
+  Untagged: false
+`
+	if got := render(t, "yaml", items); got != want {
+		t.Errorf("YAML output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestPrintYAMLScalars(t *testing.T) {
+	// Strings that look like other types stay strings
+	got := render(t, "yaml", map[string]interface{}{"s": "true", "n": "10", "empty": ""})
+	want := "empty: \"\"\nn: \"10\"\ns: \"true\"\n"
+	if got != want {
+		t.Errorf("YAML output = %q, want %q", got, want)
+	}
+}
+
+func TestPrintCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		{
+			name: "flattens nested values",
+			v: []outputItem{
+				{Name: "api", ID: "p1", Replicas: 3, Tags: []string{"a", "b"}, Labels: map[string]string{"env": "prod"},
+					Owner: &outputOwner{Email: "dev@example.com", ID: "u1"}},
+				{Name: "a,b", ID: "p2", Ratio: 1.5},
+			},
+			want: "name,id,replicas,tags,labels,owner,createdAt,Untagged,ratio\n" +
+				"api,p1,3,a;b,\"{\"\"env\"\":\"\"prod\"\"}\",\"{\"\"email\"\":\"\"dev@example.com\"\",\"\"id\"\":\"\"u1\"\"}\",,false,\n" +
+				"\"a,b\",p2,0,,,,,false,1.5\n",
+		},
+		{
+			name: "single result",
+			v:    &outputOwner{Email: "dev@example.com", ID: "u1"},
+			want: "email,id\ndev@example.com,u1\n",
+		},
+		{
+			name: "nested lists are JSON",
+			v:    []map[string]interface{}{{"matrix": [][]int{{1, 2}, {3}}}},
+			want: "matrix\n\"[[1,2],[3]]\"\n",
+		},
+		{
+			name: "empty list uses the element fields",
+			v:    []outputOwner{},
+			want: "email,id\n",
+		},
+		{
+			name: "scalars",
+			v:    []string{"a", "b"},
+			want: "value\na\nb\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render(t, "csv", tt.v); got != tt.want {
+				t.Errorf("CSV output:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONFieldNames(t *testing.T) {
+	want := []string{"name", "id", "replicas", "ratio", "tags", "labels", "owner", "createdAt", "Untagged"}
+
+	for _, v := range []interface{}{outputItem{}, &outputItem{}, []outputItem{}, []*outputItem{}, [2]outputItem{}} {
+		if got := jsonFieldNames(reflect.TypeOf(v)); !reflect.DeepEqual(got, want) {
+			t.Errorf("jsonFieldNames(%T) = %v, want %v", v, got, want)
+		}
+	}
+
+	for _, v := range []interface{}{[]string{}, 42, nil} {
+		if got := jsonFieldNames(reflect.TypeOf(v)); !reflect.DeepEqual(got, []string{"value"}) {
+			t.Errorf("jsonFieldNames(%T) = %v, want [value]", v, got)
+		}
+	}
+}
+
+func TestNewPrinter(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatTable, false},
+		{"JSON", FormatJSON, false},
+		{" yaml ", FormatYAML, false},
+		{"go-template={{.ID}}", FormatTemplate, false},
+		{"jsonpath={.id}", FormatJSONPath, false},
+		{"json=x", "", true},
+		{"template=", "", true},
+		{"template={{.ID", "", true},
+		{"jsonpath={.id", "", true},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			printer, err := NewPrinter(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewPrinter(%q) error = %v, want error: %v", tt.spec, err, tt.wantErr)
+			}
+			if err == nil && printer.Format != tt.want {
+				t.Errorf("NewPrinter(%q) format = %q, want %q", tt.spec, printer.Format, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package display
+
+import (
+	"os"
+
+	"github.com/ddod/leanmcp-cli/internal/config"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+)
+
+// ProfilesTable displays auth profiles, marking the current one with *
+func ProfilesTable(profiles []config.ProfileInfo) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"", "Profile", "Status", "Email", "Stored In", "API"})
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	for _, profile := range profiles {
+		current := ""
+		if profile.Current {
+			current = "*"
+		}
+
+		status := color.RedString("logged out")
+		if profile.LoggedIn {
+			status = color.GreenString("logged in")
+		}
+
+		api := profile.BaseURL
+		if profile.Environment != "" {
+			api = profile.Environment
+		}
+
+		table.Append([]string{
+			current,
+			profile.Name,
+			status,
+			orDash(profile.UserEmail),
+			orDash(profile.CredentialStore),
+			orDash(api),
+		})
+	}
+
+	table.Render()
+}
+
+// orDash returns value, or "-" when it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
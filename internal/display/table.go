@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/olekukonko/tablewriter"
@@ -104,14 +106,104 @@ func BuildsTable(builds []api.Build) {
 	table.Render()
 }
 
+// DeploymentsTable displays deployments in a table format
+func DeploymentsTable(deployments []api.Deployment) {
+	if len(deployments) == 0 {
+		fmt.Println("No deployments found.")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "Project", "Status", "URL", "Created", "Updated"})
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	for _, deployment := range deployments {
+		url := deployment.URL
+		if url == "" {
+			url = "-"
+		}
+		table.Append([]string{
+			shortID(deployment.ID),
+			shortID(deployment.ProjectID),
+			colorizeStatus(deployment.Status),
+			url,
+			deployment.CreatedAt.Format("2006-01-02 15:04"),
+			deployment.UpdatedAt.Format("2006-01-02 15:04"),
+		})
+	}
+
+	table.Render()
+}
+
+// APIKeysTable displays API keys in a table format, marking the key in use
+// with *
+func APIKeysTable(keys []api.APIKeyInfo, currentID string) {
+	if len(keys) == 0 {
+		fmt.Println("No API keys found.")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"", "ID", "Name", "Scopes", "Status", "Created", "Expires"})
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	for _, key := range keys {
+		current := ""
+		if key.ID == currentID {
+			current = "*"
+		}
+
+		expires := "Never"
+		if key.ExpiresAt != nil {
+			expires = key.ExpiresAt.Format("2006-01-02 15:04")
+		}
+
+		table.Append([]string{
+			current,
+			shortID(key.ID),
+			key.Name,
+			strings.Join(key.Scopes, ","),
+			colorizeStatus(APIKeyStatus(key)),
+			key.CreatedAt.Format("2006-01-02 15:04"),
+			expires,
+		})
+	}
+
+	table.Render()
+}
+
+// APIKeyStatus returns "active", "inactive" or "expired"
+func APIKeyStatus(key api.APIKeyInfo) string {
+	switch {
+	case !key.IsActive:
+		return "inactive"
+	case key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()):
+		return "expired"
+	default:
+		return "active"
+	}
+}
+
+// shortID truncates an ID for readability, leaving short IDs untouched
+func shortID(id string) string {
+	if len(id) <= 8 {
+		return id
+	}
+	return id[:8] + "..."
+}
+
 // colorizeStatus adds color to status strings
 func colorizeStatus(status string) string {
 	switch status {
-	case "active", "running", "success", "completed":
+	case "active", "running", "success", "completed", "deployed", "live":
 		return color.GreenString(status)
 	case "pending", "building", "deploying":
 		return color.YellowString(status)
-	case "failed", "error", "inactive":
+	case "failed", "error", "inactive", "stopped", "expired":
 		return color.RedString(status)
 	default:
 		return status
@@ -137,6 +229,61 @@ func PrintProject(project *api.Project) {
 	fmt.Printf("%s %s\n", color.CyanString("Updated:"), project.UpdatedAt.Format("2006-01-02 15:04:05"))
 }
 
+// PrintDeployment displays detailed deployment information
+func PrintDeployment(deployment *api.Deployment) {
+	fmt.Printf("%s %s\n", color.CyanString("Deployment:"), deployment.ID)
+	fmt.Printf("%s %s\n", color.CyanString("Project ID:"), deployment.ProjectID)
+	if deployment.BuildID != "" {
+		fmt.Printf("%s %s\n", color.CyanString("Build ID:"), deployment.BuildID)
+	}
+	fmt.Printf("%s %s\n", color.CyanString("Status:"), colorizeStatus(deployment.Status))
+	if deployment.URL != "" {
+		fmt.Printf("%s %s\n", color.CyanString("URL:"), deployment.URL)
+	}
+	fmt.Printf("%s %s\n", color.CyanString("Created:"), deployment.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Printf("%s %s\n", color.CyanString("Updated:"), deployment.UpdatedAt.Format("2006-01-02 15:04:05"))
+}
+
+// PrintDeploymentLogs displays deployment log entries
+func PrintDeploymentLogs(entries []api.DeploymentLogEntry) {
+	if len(entries) == 0 {
+		fmt.Println("No logs found.")
+		return
+	}
+
+	for _, entry := range entries {
+		PrintDeploymentLogEntry(entry)
+	}
+}
+
+// PrintDeploymentLogEntry displays a single deployment log line
+func PrintDeploymentLogEntry(entry api.DeploymentLogEntry) {
+	level := entry.Level
+	if level == "" {
+		level = "info"
+	}
+
+	fmt.Printf("%s %s %s\n",
+		color.HiBlackString(entry.Timestamp.Format("2006-01-02 15:04:05")),
+		colorizeLogLevel(level),
+		entry.Message)
+}
+
+// colorizeLogLevel adds color to log level strings
+func colorizeLogLevel(level string) string {
+	label := fmt.Sprintf("%-5s", strings.ToUpper(level))
+	switch strings.ToLower(level) {
+	case "error", "fatal":
+		return color.RedString(label)
+	case "warn", "warning":
+		return color.YellowString(label)
+	case "debug", "trace":
+		return color.HiBlackString(label)
+	default:
+		return color.GreenString(label)
+	}
+}
+
 // PrintChat displays detailed chat information
 func PrintChat(chat *api.Chat) {
 	fmt.Printf("%s %s\n", color.CyanString("Chat:"), color.WhiteString(chat.Title))
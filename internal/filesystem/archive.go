@@ -0,0 +1,387 @@
+package filesystem
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ArchiveFormat is the container and compression used for uploads
+type ArchiveFormat string
+
+// Supported archive formats
+const (
+	FormatZip     ArchiveFormat = "zip"
+	FormatTarGz   ArchiveFormat = "tar.gz"
+	FormatTarZstd ArchiveFormat = "tar.zst"
+)
+
+// DefaultArchiveFormat is used when neither the user nor the backend picks one
+const DefaultArchiveFormat = FormatZip
+
+// ArchiveFormats lists every supported format
+var ArchiveFormats = []ArchiveFormat{FormatZip, FormatTarGz, FormatTarZstd}
+
+// ArchiveOptions selects how a project archive is written. The zero value
+// writes a zip with the default compression level.
+type ArchiveOptions struct {
+	Format ArchiveFormat
+	// CompressionLevel is 1-9 for zip and tar.gz and 1-22 for tar.zst;
+	// 0 selects the format's default
+	CompressionLevel int
+}
+
+// ParseArchiveFormat validates a format name. "tgz", "zst" and "zstd" are
+// accepted as aliases.
+func ParseArchiveFormat(value string) (ArchiveFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "zip":
+		return FormatZip, nil
+	case "tar.gz", "tgz":
+		return FormatTarGz, nil
+	case "tar.zst", "tar.zstd", "zst", "zstd":
+		return FormatTarZstd, nil
+	default:
+		return "", fmt.Errorf("unsupported archive format %q (use zip, tar.gz or tar.zst)", value)
+	}
+}
+
+// Extension returns the file extension including the leading dot
+func (f ArchiveFormat) Extension() string {
+	switch f {
+	case FormatTarGz:
+		return ".tar.gz"
+	case FormatTarZstd:
+		return ".tar.zst"
+	default:
+		return ".zip"
+	}
+}
+
+// ContentType returns the MIME type sent with uploads
+func (f ArchiveFormat) ContentType() string {
+	switch f {
+	case FormatTarGz:
+		return "application/gzip"
+	case FormatTarZstd:
+		return "application/zstd"
+	default:
+		return "application/zip"
+	}
+}
+
+// FileName returns the name an archive of this format is uploaded as
+func (f ArchiveFormat) FileName() string {
+	return "project" + f.Extension()
+}
+
+// Validate checks that the format is known and the level is in its range
+func (o ArchiveOptions) Validate() error {
+	format, err := ParseArchiveFormat(string(o.format()))
+	if err != nil {
+		return err
+	}
+
+	if o.CompressionLevel == 0 {
+		return nil
+	}
+	max := 9
+	if format == FormatTarZstd {
+		max = 22
+	}
+	if o.CompressionLevel < 1 || o.CompressionLevel > max {
+		return fmt.Errorf("compression level for %s must be between 1 and %d", format, max)
+	}
+	return nil
+}
+
+// format returns the selected format, defaulting to DefaultArchiveFormat
+func (o ArchiveOptions) format() ArchiveFormat {
+	if o.Format == "" {
+		return DefaultArchiveFormat
+	}
+	return o.Format
+}
+
+// zipDefaultLevel is the Deflate level archive/zip uses on its own
+const zipDefaultLevel = 5
+
+// archiveWriter adds entries to an archive of some format. Entry names are
+// slash-separated and every entry gets archiveModTime.
+type archiveWriter interface {
+	// WriteFile adds a regular file of the given size read from r
+	WriteFile(name string, mode os.FileMode, size int64, r io.Reader) error
+	// WriteSymlink adds a symlink pointing at target
+	WriteSymlink(name, target string) error
+	// Close finishes the archive; it does not close the underlying writer
+	Close() error
+}
+
+// compressedEntry is file content compressed ahead of time by a worker
+type compressedEntry struct {
+	data  []byte
+	crc32 uint32
+	size  int64 // uncompressed size
+}
+
+// entryCompressor is implemented by archive writers that compress every entry
+// on its own, so workers can compress entries in parallel and the writer only
+// copies the results. CompressEntry must be safe for concurrent use.
+type entryCompressor interface {
+	CompressEntry(content []byte) (*compressedEntry, error)
+	WriteCompressed(name string, mode os.FileMode, entry *compressedEntry) error
+}
+
+// newArchiveWriter creates a writer for the format selected by options
+func newArchiveWriter(w io.Writer, options ArchiveOptions) (archiveWriter, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	level := options.CompressionLevel
+	switch options.format() {
+	case FormatTarGz:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		compressor, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			return nil, err
+		}
+		return newTarArchiveWriter(compressor), nil
+
+	case FormatTarZstd:
+		encoderLevel := zstd.SpeedDefault
+		if level != 0 {
+			encoderLevel = zstd.EncoderLevelFromZstd(level)
+		}
+		compressor, err := zstd.NewWriter(w, zstd.WithEncoderLevel(encoderLevel))
+		if err != nil {
+			return nil, err
+		}
+		return newTarArchiveWriter(compressor), nil
+
+	default:
+		zipWriter := zip.NewWriter(w)
+		if level == 0 {
+			level = zipDefaultLevel
+		} else {
+			zipWriter.RegisterCompressor(zip.Deflate, func(out io.Writer) (io.WriteCloser, error) {
+				return flate.NewWriter(out, level)
+			})
+		}
+		return &zipArchiveWriter{w: zipWriter, level: level}, nil
+	}
+}
+
+// zipArchiveWriter writes Deflate zip archives
+type zipArchiveWriter struct {
+	w     *zip.Writer
+	level int
+	// flateWriters pools writers for CompressEntry
+	flateWriters sync.Pool
+}
+
+func (a *zipArchiveWriter) WriteFile(name string, mode os.FileMode, size int64, r io.Reader) error {
+	// Build the header from normalized metadata only, so the archive does not
+	// depend on modification times, owners or umask
+	header := &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: archiveModTime,
+	}
+	header.SetMode(mode)
+
+	writer, err := a.w.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("failed to create zip entry: %w", err)
+	}
+
+	n, err := io.Copy(writer, r)
+	if err != nil {
+		return fmt.Errorf("failed to copy file content: %w", err)
+	}
+	return checkCopied(size, n)
+}
+
+// WriteSymlink stores the link the way Info-ZIP does: an uncompressed entry
+// with the symlink mode whose content is the target
+func (a *zipArchiveWriter) WriteSymlink(name, target string) error {
+	header := &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Store,
+		Modified: archiveModTime,
+	}
+	header.SetMode(os.ModeSymlink | 0777)
+
+	writer, err := a.w.CreateHeader(header)
+	if err != nil {
+		return fmt.Errorf("failed to create zip entry: %w", err)
+	}
+
+	if _, err := io.WriteString(writer, target); err != nil {
+		return fmt.Errorf("failed to write link target: %w", err)
+	}
+	return nil
+}
+
+// CompressEntry deflates content the same way the zip writer would
+func (a *zipArchiveWriter) CompressEntry(content []byte) (*compressedEntry, error) {
+	var buf bytes.Buffer
+	compressor, err := a.flateWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	defer a.flateWriters.Put(compressor)
+	if _, err := compressor.Write(content); err != nil {
+		return nil, err
+	}
+	if err := compressor.Close(); err != nil {
+		return nil, err
+	}
+
+	entry := &compressedEntry{
+		data:  buf.Bytes(),
+		crc32: crc32.ChecksumIEEE(content),
+		size:  int64(len(content)),
+	}
+	return entry, nil
+}
+
+// flateWriter returns a Deflate writer at the archive's level writing to w.
+// Writers are reused, since allocating one costs far more than compressing a
+// typical source file.
+func (a *zipArchiveWriter) flateWriter(w io.Writer) (*flate.Writer, error) {
+	if compressor, ok := a.flateWriters.Get().(*flate.Writer); ok {
+		compressor.Reset(w)
+		return compressor, nil
+	}
+	return flate.NewWriter(w, a.level)
+}
+
+// WriteCompressed adds an entry compressed by CompressEntry. CreateRaw writes
+// the header as given, so the fields CreateHeader would fill in are set here.
+func (a *zipArchiveWriter) WriteCompressed(name string, mode os.FileMode, entry *compressedEntry) error {
+	header := &zip.FileHeader{
+		Name:               name,
+		Method:             zip.Deflate,
+		CRC32:              entry.crc32,
+		CompressedSize64:   uint64(len(entry.data)),
+		UncompressedSize64: uint64(entry.size),
+		ReaderVersion:      zipVersion20,
+	}
+	header.SetMode(mode)
+	header.CreatorVersion |= zipVersion20
+	header.ModifiedDate, header.ModifiedTime = msDosTime(archiveModTime)
+	if !isASCII(name) && utf8.ValidString(name) {
+		header.Flags |= zipFlagUTF8
+	}
+
+	writer, err := a.w.CreateRaw(header)
+	if err != nil {
+		return fmt.Errorf("failed to create zip entry: %w", err)
+	}
+
+	if _, err := writer.Write(entry.data); err != nil {
+		return fmt.Errorf("failed to copy file content: %w", err)
+	}
+	return nil
+}
+
+func (a *zipArchiveWriter) Close() error {
+	return a.w.Close()
+}
+
+// Zip header values set by CreateHeader but not by CreateRaw
+const (
+	zipVersion20 = 20    // version needed to extract Deflate entries
+	zipFlagUTF8  = 0x800 // entry name is UTF-8
+)
+
+// msDosTime converts t to the date and time fields of a zip header
+func msDosTime(t time.Time) (date, clock uint16) {
+	date = uint16(t.Day() + int(t.Month())<<5 + (t.Year()-1980)<<9)
+	clock = uint16(t.Second()/2 + t.Minute()<<5 + t.Hour()<<11)
+	return date, clock
+}
+
+// isASCII reports whether s contains only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// tarArchiveWriter writes a tar stream through a compressor
+type tarArchiveWriter struct {
+	w          *tar.Writer
+	compressor io.WriteCloser
+}
+
+func newTarArchiveWriter(compressor io.WriteCloser) *tarArchiveWriter {
+	return &tarArchiveWriter{w: tar.NewWriter(compressor), compressor: compressor}
+}
+
+func (a *tarArchiveWriter) WriteFile(name string, mode os.FileMode, size int64, r io.Reader) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     int64(mode.Perm()),
+		Size:     size,
+		ModTime:  archiveModTime,
+	}
+	if err := a.w.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to create tar entry: %w", err)
+	}
+
+	n, err := io.Copy(a.w, r)
+	if err != nil {
+		return fmt.Errorf("failed to copy file content: %w", err)
+	}
+	return checkCopied(size, n)
+}
+
+func (a *tarArchiveWriter) WriteSymlink(name, target string) error {
+	header := &tar.Header{
+		Typeflag: tar.TypeSymlink,
+		Name:     name,
+		Linkname: target,
+		Mode:     0777,
+		ModTime:  archiveModTime,
+	}
+	if err := a.w.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to create tar entry: %w", err)
+	}
+	return nil
+}
+
+// checkCopied reports a file that changed size while it was being archived,
+// which would leave the manifest or a tar header out of step with the content
+func checkCopied(size, copied int64) error {
+	if copied != size {
+		return fmt.Errorf("file changed while archiving (expected %d bytes, read %d)", size, copied)
+	}
+	return nil
+}
+
+func (a *tarArchiveWriter) Close() error {
+	if err := a.w.Close(); err != nil {
+		return err
+	}
+	return a.compressor.Close()
+}
@@ -0,0 +1,591 @@
+package filesystem
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Frameworks reported by DetectFramework. A project whose manifest names no
+// MCP SDK is reported by its runtime (node, python or go).
+const (
+	FrameworkMCPTypeScript = "mcp-typescript" // @modelcontextprotocol/sdk or fastmcp on npm
+	FrameworkFastMCP       = "fastmcp"        // FastMCP for Python
+	FrameworkMCPPython     = "mcp-python"     // the official Python SDK (mcp)
+	FrameworkMCPGo         = "mcp-go"         // a Go MCP SDK
+	FrameworkDocker        = "docker"         // only a Dockerfile was found
+)
+
+// Runtimes reported by DetectFramework
+const (
+	RuntimeNode   = "node"
+	RuntimePython = "python"
+	RuntimeGo     = "go"
+)
+
+// FrameworkInfo describes the MCP server found in a project directory
+type FrameworkInfo struct {
+	Framework string
+	Language  string // typescript, javascript, python or go
+	Runtime   string
+	// RuntimeVersion is a version or constraint as written in the project,
+	// e.g. ">=18" from package.json or "1.22" from go.mod
+	RuntimeVersion string
+	// Entrypoint is the file, module or command that starts the server
+	Entrypoint string
+	// Source is the file the framework was detected from
+	Source string
+
+	sdk bool // an MCP SDK dependency was found
+}
+
+// String summarizes the framework for status messages
+func (f *FrameworkInfo) String() string {
+	var details []string
+	if f.Runtime != "" && f.Runtime != f.Framework {
+		details = append(details, strings.TrimSpace(f.Runtime+" "+f.RuntimeVersion))
+	} else if f.RuntimeVersion != "" {
+		details = append(details, f.RuntimeVersion)
+	}
+	if f.Entrypoint != "" {
+		details = append(details, f.Entrypoint)
+	}
+
+	if len(details) == 0 {
+		return f.Framework
+	}
+	return fmt.Sprintf("%s (%s)", f.Framework, strings.Join(details, ", "))
+}
+
+// mcpSDKs maps dependency names to the framework they indicate, per runtime.
+// Python names are normalized as in PEP 503.
+var mcpSDKs = map[string]map[string]string{
+	RuntimeNode: {
+		"@modelcontextprotocol/sdk": FrameworkMCPTypeScript,
+		"fastmcp":                   FrameworkMCPTypeScript,
+	},
+	RuntimePython: {
+		"fastmcp": FrameworkFastMCP,
+		"mcp":     FrameworkMCPPython,
+	},
+	RuntimeGo: {
+		"github.com/modelcontextprotocol/go-sdk": FrameworkMCPGo,
+		"github.com/mark3labs/mcp-go":            FrameworkMCPGo,
+		"github.com/metoro-io/mcp-golang":        FrameworkMCPGo,
+	},
+}
+
+// frameworkDetectors inspect the manifest of one runtime each. They return
+// nil when the project has no such manifest.
+var frameworkDetectors = []func(projectPath string) (*FrameworkInfo, error){
+	detectNodeFramework,
+	detectPythonFramework,
+	detectGoFramework,
+}
+
+// DetectFramework identifies the MCP SDK, runtime version and entrypoint of
+// the project from package.json, pyproject.toml, requirements.txt, go.mod and
+// Dockerfile in its root. A manifest with an MCP SDK dependency wins over one
+// without; a Dockerfile for the same runtime fills in what the manifest leaves
+// open. It returns nil if nothing was recognized.
+func DetectFramework(projectPath string) (*FrameworkInfo, error) {
+	var detected *FrameworkInfo
+	for _, detect := range frameworkDetectors {
+		info, err := detect(projectPath)
+		if err != nil {
+			return nil, err
+		}
+		if info == nil {
+			continue
+		}
+		if detected == nil || (info.sdk && !detected.sdk) {
+			detected = info
+		}
+	}
+
+	docker, err := detectDockerfile(projectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case detected == nil:
+		return docker, nil
+	case docker != nil && (docker.Runtime == "" || docker.Runtime == detected.Runtime):
+		if detected.RuntimeVersion == "" {
+			detected.RuntimeVersion = docker.RuntimeVersion
+		}
+		if detected.Entrypoint == "" {
+			detected.Entrypoint = docker.Entrypoint
+		}
+	}
+	return detected, nil
+}
+
+// packageJSON holds the package.json fields used for detection
+type packageJSON struct {
+	Main            string            `json:"main"`
+	Bin             json.RawMessage   `json:"bin"`
+	Scripts         map[string]string `json:"scripts"`
+	Engines         map[string]string `json:"engines"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+// nodeEntrypoints are tried in order when package.json names no entrypoint
+var nodeEntrypoints = []string{"src/index.ts", "index.ts", "src/index.js", "index.js", "server.js"}
+
+func detectNodeFramework(projectPath string) (*FrameworkInfo, error) {
+	data, err := readProjectFile(projectPath, "package.json")
+	if data == nil || err != nil {
+		return nil, err
+	}
+
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("invalid package.json: %w", err)
+	}
+
+	info := &FrameworkInfo{
+		Framework:      RuntimeNode,
+		Language:       "javascript",
+		Runtime:        RuntimeNode,
+		RuntimeVersion: pkg.Engines["node"],
+		Source:         "package.json",
+	}
+
+	deps := make([]string, 0, len(pkg.Dependencies)+len(pkg.DevDependencies))
+	for name := range pkg.Dependencies {
+		deps = append(deps, name)
+	}
+	for name := range pkg.DevDependencies {
+		deps = append(deps, name)
+	}
+	info.setSDK(deps)
+
+	_, hasTypeScript := pkg.Dependencies["typescript"]
+	if _, ok := pkg.DevDependencies["typescript"]; ok {
+		hasTypeScript = true
+	}
+	if hasTypeScript || projectFileExists(projectPath, "tsconfig.json") {
+		info.Language = "typescript"
+	}
+
+	if info.RuntimeVersion == "" {
+		info.RuntimeVersion = readVersionFile(projectPath, ".nvmrc", ".node-version")
+	}
+
+	switch {
+	case pkg.Main != "":
+		info.Entrypoint = path.Clean(pkg.Main)
+	case packageBin(pkg.Bin) != "":
+		info.Entrypoint = packageBin(pkg.Bin)
+	case scriptEntrypoint(pkg.Scripts["start"]) != "":
+		info.Entrypoint = scriptEntrypoint(pkg.Scripts["start"])
+	default:
+		info.Entrypoint = firstExisting(projectPath, nodeEntrypoints)
+	}
+
+	return info, nil
+}
+
+// packageBin returns the executable of a package.json bin field, which is a
+// path or a map of command names to paths; for a map the first command by
+// name is used
+func packageBin(raw json.RawMessage) string {
+	var single string
+	if json.Unmarshal(raw, &single) == nil && single != "" {
+		return path.Clean(single)
+	}
+
+	var commands map[string]string
+	if json.Unmarshal(raw, &commands) != nil || len(commands) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return path.Clean(commands[names[0]])
+}
+
+// scriptEntrypoint returns the first script file in a command such as
+// "node dist/index.js" or "tsx src/server.ts"
+func scriptEntrypoint(script string) string {
+	for _, field := range strings.Fields(script) {
+		switch path.Ext(field) {
+		case ".js", ".mjs", ".cjs", ".ts", ".mts":
+			return path.Clean(field)
+		}
+	}
+	return ""
+}
+
+// pyProject holds the pyproject.toml fields used for detection
+type pyProject struct {
+	Project struct {
+		RequiresPython string            `toml:"requires-python"`
+		Dependencies   []string          `toml:"dependencies"`
+		Scripts        map[string]string `toml:"scripts"`
+	} `toml:"project"`
+	Tool struct {
+		Poetry struct {
+			Dependencies map[string]interface{} `toml:"dependencies"`
+			Scripts      map[string]interface{} `toml:"scripts"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+// pythonEntrypoints are tried in order when pyproject.toml names no script
+var pythonEntrypoints = []string{"server.py", "main.py", "app.py", "src/server.py", "src/main.py"}
+
+func detectPythonFramework(projectPath string) (*FrameworkInfo, error) {
+	pyprojectData, err := readProjectFile(projectPath, "pyproject.toml")
+	if err != nil {
+		return nil, err
+	}
+	requirementsData, err := readProjectFile(projectPath, "requirements.txt")
+	if err != nil {
+		return nil, err
+	}
+	if pyprojectData == nil && requirementsData == nil {
+		return nil, nil
+	}
+
+	info := &FrameworkInfo{
+		Framework: RuntimePython,
+		Language:  "python",
+		Runtime:   RuntimePython,
+		Source:    "requirements.txt",
+	}
+
+	var deps []string
+	scripts := map[string]string{}
+	if pyprojectData != nil {
+		var project pyProject
+		if err := toml.Unmarshal(pyprojectData, &project); err != nil {
+			return nil, fmt.Errorf("invalid pyproject.toml: %w", err)
+		}
+		info.Source = "pyproject.toml"
+		info.RuntimeVersion = project.Project.RequiresPython
+
+		for _, requirement := range project.Project.Dependencies {
+			deps = append(deps, requirementName(requirement))
+		}
+		for name, value := range project.Tool.Poetry.Dependencies {
+			if name == "python" {
+				if version, ok := value.(string); ok && info.RuntimeVersion == "" {
+					info.RuntimeVersion = version
+				}
+				continue
+			}
+			deps = append(deps, normalizePythonName(name))
+		}
+
+		for name, target := range project.Project.Scripts {
+			scripts[name] = target
+		}
+		for name, value := range project.Tool.Poetry.Scripts {
+			if target, ok := value.(string); ok {
+				scripts[name] = target
+			}
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(requirementsData))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		deps = append(deps, requirementName(line))
+	}
+
+	info.setSDK(deps)
+
+	if info.RuntimeVersion == "" {
+		info.RuntimeVersion = readVersionFile(projectPath, ".python-version")
+	}
+
+	if len(scripts) > 0 {
+		names := make([]string, 0, len(scripts))
+		for name := range scripts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		info.Entrypoint = scripts[names[0]]
+	} else {
+		info.Entrypoint = firstExisting(projectPath, pythonEntrypoints)
+	}
+
+	return info, nil
+}
+
+// requirementName returns the normalized package name of a PEP 508
+// requirement such as "mcp[cli]>=1.2" or "fastmcp ; python_version>'3.9'"
+func requirementName(requirement string) string {
+	end := strings.IndexFunc(requirement, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.')
+	})
+	if end >= 0 {
+		requirement = requirement[:end]
+	}
+	return normalizePythonName(requirement)
+}
+
+// normalizePythonName lowercases a package name and replaces _ and . with -,
+// so "FastMCP" matches "fastmcp" the way pip compares names
+func normalizePythonName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '.' {
+			return '-'
+		}
+		return r
+	}, name)
+}
+
+func detectGoFramework(projectPath string) (*FrameworkInfo, error) {
+	data, err := readProjectFile(projectPath, "go.mod")
+	if data == nil || err != nil {
+		return nil, err
+	}
+
+	info := &FrameworkInfo{
+		Framework: RuntimeGo,
+		Language:  "go",
+		Runtime:   RuntimeGo,
+		Source:    "go.mod",
+	}
+
+	// Only the go directive and module paths are needed, so go.mod is read
+	// line by line instead of fully parsed
+	var deps []string
+	inRequire := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case inRequire:
+			if fields[0] == ")" {
+				inRequire = false
+			} else {
+				deps = append(deps, fields[0])
+			}
+		case fields[0] == "go" && len(fields) > 1:
+			info.RuntimeVersion = fields[1]
+		case fields[0] == "require" && len(fields) > 1:
+			if fields[1] == "(" {
+				inRequire = true
+			} else {
+				deps = append(deps, fields[1])
+			}
+		}
+	}
+
+	info.setSDK(deps)
+	info.Entrypoint = goMainPackage(projectPath)
+
+	return info, nil
+}
+
+// goMainPackage returns the package path of main.go in the project root or,
+// failing that, the first cmd/<name>/main.go
+func goMainPackage(projectPath string) string {
+	if projectFileExists(projectPath, "main.go") {
+		return "."
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(projectPath, "cmd", "*", "main.go"))
+	if len(matches) == 0 {
+		return ""
+	}
+	sort.Strings(matches)
+	return "./cmd/" + filepath.Base(filepath.Dir(matches[0]))
+}
+
+// dockerImageRuntimes maps official base images to runtimes
+var dockerImageRuntimes = map[string]string{
+	"node":   RuntimeNode,
+	"python": RuntimePython,
+	"golang": RuntimeGo,
+}
+
+func detectDockerfile(projectPath string) (*FrameworkInfo, error) {
+	data, err := readProjectFile(projectPath, "Dockerfile")
+	if data == nil || err != nil {
+		return nil, err
+	}
+
+	info := &FrameworkInfo{
+		Framework: FrameworkDocker,
+		Source:    "Dockerfile",
+	}
+
+	// Only the final stage is what runs, so later instructions override
+	// earlier ones
+	var entrypoint, command string
+	for _, instruction := range dockerInstructions(data) {
+		keyword, args, _ := strings.Cut(instruction, " ")
+		args = strings.TrimSpace(args)
+
+		switch strings.ToUpper(keyword) {
+		case "FROM":
+			info.Runtime, info.RuntimeVersion = dockerImageRuntime(args)
+			entrypoint, command = "", ""
+		case "ENTRYPOINT":
+			entrypoint = dockerCommand(args)
+		case "CMD":
+			command = dockerCommand(args)
+		}
+	}
+
+	info.Entrypoint = strings.TrimSpace(entrypoint + " " + command)
+	return info, nil
+}
+
+// dockerInstructions splits a Dockerfile into instructions, joining lines
+// continued with a backslash and dropping comments
+func dockerInstructions(data []byte) []string {
+	var instructions []string
+	var current strings.Builder
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		continued := strings.HasSuffix(line, "\\")
+		line = strings.TrimSuffix(line, "\\")
+		if current.Len() > 0 {
+			current.WriteByte(' ')
+		}
+		current.WriteString(strings.TrimSpace(line))
+
+		if !continued && current.Len() > 0 {
+			instructions = append(instructions, current.String())
+			current.Reset()
+		}
+	}
+	if current.Len() > 0 {
+		instructions = append(instructions, current.String())
+	}
+
+	return instructions
+}
+
+// dockerImageRuntime maps the image of a FROM instruction such as
+// "node:20-alpine AS build" to a runtime and version
+func dockerImageRuntime(from string) (string, string) {
+	fields := strings.Fields(from)
+	for len(fields) > 0 && strings.HasPrefix(fields[0], "--") {
+		fields = fields[1:] // --platform=...
+	}
+	if len(fields) == 0 {
+		return "", ""
+	}
+
+	image, tag, _ := strings.Cut(fields[0], ":")
+	runtime, ok := dockerImageRuntimes[path.Base(image)]
+	if !ok {
+		return "", ""
+	}
+
+	// Keep the version part of tags like 3.12-slim
+	end := strings.IndexFunc(tag, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r == '.')
+	})
+	if end >= 0 {
+		tag = tag[:end]
+	}
+	return runtime, tag
+}
+
+// dockerCommand returns the command of an ENTRYPOINT or CMD instruction in
+// either exec form (a JSON array) or shell form
+func dockerCommand(args string) string {
+	var exec []string
+	if json.Unmarshal([]byte(args), &exec) == nil {
+		return strings.Join(exec, " ")
+	}
+	return args
+}
+
+// setSDK sets the framework from the first known MCP SDK among deps
+func (f *FrameworkInfo) setSDK(deps []string) {
+	sdks := mcpSDKs[f.Runtime]
+
+	// Sort so the result does not depend on map order, and prefer the
+	// framework over the SDK it builds on (fastmcp depends on mcp)
+	sort.Strings(deps)
+	for _, dep := range deps {
+		if framework, ok := sdks[dep]; ok && (!f.sdk || framework == FrameworkFastMCP) {
+			f.Framework = framework
+			f.sdk = true
+		}
+	}
+}
+
+// readProjectFile reads a file in the project root, returning nil if it does
+// not exist
+func readProjectFile(projectPath, name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// readVersionFile returns the first line of the first version file found,
+// such as .nvmrc or .python-version
+func readVersionFile(projectPath string, names ...string) string {
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(projectPath, name))
+		if err != nil {
+			continue
+		}
+		line, _, _ := strings.Cut(string(data), "\n")
+		if version := strings.TrimPrefix(strings.TrimSpace(line), "v"); version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+// projectFileExists reports whether a regular file exists in the project
+func projectFileExists(projectPath, name string) bool {
+	info, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(name)))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// firstExisting returns the first of the slash-separated paths that exists
+func firstExisting(projectPath string, names []string) string {
+	for _, name := range names {
+		if projectFileExists(projectPath, name) {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,215 @@
+package filesystem
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetectFramework(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  *FrameworkInfo
+	}{
+		{
+			name: "typescript SDK",
+			files: map[string]string{
+				"package.json": `{
+					"main": "./dist/index.js",
+					"engines": {"node": ">=18"},
+					"dependencies": {"@modelcontextprotocol/sdk": "^1.0.0"},
+					"devDependencies": {"typescript": "^5.0.0"}
+				}`,
+			},
+			want: &FrameworkInfo{Framework: FrameworkMCPTypeScript, Language: "typescript", Runtime: RuntimeNode,
+				RuntimeVersion: ">=18", Entrypoint: "dist/index.js", Source: "package.json"},
+		},
+		{
+			name: "fastmcp on npm with bin map and nvmrc",
+			files: map[string]string{
+				"package.json":  `{"bin": {"zeta": "bin/z.js", "alpha": "./bin/a.js"}, "dependencies": {"fastmcp": "1.0.0"}}`,
+				"tsconfig.json": `{}`,
+				".nvmrc":        "v20.11.0\n",
+			},
+			want: &FrameworkInfo{Framework: FrameworkMCPTypeScript, Language: "typescript", Runtime: RuntimeNode,
+				RuntimeVersion: "20.11.0", Entrypoint: "bin/a.js", Source: "package.json"},
+		},
+		{
+			name: "node without SDK uses start script",
+			files: map[string]string{
+				"package.json": `{"bin": "", "scripts": {"start": "NODE_ENV=production tsx src/server.ts --port 3000"}}`,
+			},
+			want: &FrameworkInfo{Framework: RuntimeNode, Language: "javascript", Runtime: RuntimeNode,
+				Entrypoint: "src/server.ts", Source: "package.json"},
+		},
+		{
+			name: "node falls back to known entrypoints",
+			files: map[string]string{
+				"package.json": `{}`,
+				"index.js":     "",
+				"server.js":    "",
+			},
+			want: &FrameworkInfo{Framework: RuntimeNode, Language: "javascript", Runtime: RuntimeNode,
+				Entrypoint: "index.js", Source: "package.json"},
+		},
+		{
+			name: "pyproject with PEP 508 requirement",
+			files: map[string]string{
+				"pyproject.toml": `
+[project]
+requires-python = ">=3.10"
+dependencies = ["httpx>=0.27", "mcp[cli]>=1.2.0"]
+
+[project.scripts]
+weather = "weather.server:main"
+`,
+			},
+			want: &FrameworkInfo{Framework: FrameworkMCPPython, Language: "python", Runtime: RuntimePython,
+				RuntimeVersion: ">=3.10", Entrypoint: "weather.server:main", Source: "pyproject.toml"},
+		},
+		{
+			name: "poetry prefers fastmcp over mcp",
+			files: map[string]string{
+				"pyproject.toml": `
+[tool.poetry.dependencies]
+python = "^3.11"
+mcp = "^1.2"
+FastMCP = { version = "^2.0", extras = ["cli"] }
+
+[tool.poetry.scripts]
+serve = "app.main:run"
+`,
+			},
+			want: &FrameworkInfo{Framework: FrameworkFastMCP, Language: "python", Runtime: RuntimePython,
+				RuntimeVersion: "^3.11", Entrypoint: "app.main:run", Source: "pyproject.toml"},
+		},
+		{
+			name: "requirements with markers and options",
+			files: map[string]string{
+				"requirements.txt": "# deps\n-r base.txt\n--index-url https://example.com\nfast_mcp==0.1\nFastMCP ; python_version > '3.9'\n",
+				".python-version":  "3.12.1\n",
+				"main.py":          "",
+				"src/server.py":    "",
+			},
+			want: &FrameworkInfo{Framework: FrameworkFastMCP, Language: "python", Runtime: RuntimePython,
+				RuntimeVersion: "3.12.1", Entrypoint: "main.py", Source: "requirements.txt"},
+		},
+		{
+			name: "python without SDK",
+			files: map[string]string{
+				"requirements.txt": "flask\n",
+			},
+			want: &FrameworkInfo{Framework: RuntimePython, Language: "python", Runtime: RuntimePython,
+				Source: "requirements.txt"},
+		},
+		{
+			name: "go.mod require block",
+			files: map[string]string{
+				"go.mod": `module example.com/server
+
+go 1.22
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/mark3labs/mcp-go v0.8.0 // indirect
+)
+`,
+				"cmd/zz/main.go":     "",
+				"cmd/server/main.go": "",
+			},
+			want: &FrameworkInfo{Framework: FrameworkMCPGo, Language: "go", Runtime: RuntimeGo,
+				RuntimeVersion: "1.22", Entrypoint: "./cmd/server", Source: "go.mod"},
+		},
+		{
+			name: "go.mod single require",
+			files: map[string]string{
+				"go.mod":  "module m\n\ngo 1.23.0\n\nrequire github.com/modelcontextprotocol/go-sdk v0.2.0\n",
+				"main.go": "",
+			},
+			want: &FrameworkInfo{Framework: FrameworkMCPGo, Language: "go", Runtime: RuntimeGo,
+				RuntimeVersion: "1.23.0", Entrypoint: ".", Source: "go.mod"},
+		},
+		{
+			name: "SDK manifest wins over one without",
+			files: map[string]string{
+				"package.json":     `{"main": "tools.js"}`,
+				"requirements.txt": "mcp\n",
+			},
+			want: &FrameworkInfo{Framework: FrameworkMCPPython, Language: "python", Runtime: RuntimePython,
+				Source: "requirements.txt"},
+		},
+		{
+			name: "multi-stage Dockerfile only",
+			files: map[string]string{
+				"Dockerfile": `# build
+FROM --platform=linux/amd64 golang:1.22 AS build
+CMD ["go", "build"]
+
+FROM node:20.11-alpine
+ENTRYPOINT ["node", \
+    "dist/index.js"]
+`,
+			},
+			want: &FrameworkInfo{Framework: FrameworkDocker, Runtime: RuntimeNode, RuntimeVersion: "20.11",
+				Entrypoint: "node dist/index.js", Source: "Dockerfile"},
+		},
+		{
+			name: "Dockerfile fills in the manifest",
+			files: map[string]string{
+				"package.json": `{"dependencies": {"@modelcontextprotocol/sdk": "1"}}`,
+				"Dockerfile":   "FROM node:22-slim\nENTRYPOINT [\"node\"]\nCMD server.mjs\n",
+			},
+			want: &FrameworkInfo{Framework: FrameworkMCPTypeScript, Language: "javascript", Runtime: RuntimeNode,
+				RuntimeVersion: "22", Entrypoint: "node server.mjs", Source: "package.json"},
+		},
+		{
+			name: "Dockerfile for another runtime is ignored",
+			files: map[string]string{
+				"go.mod":     "module m\n",
+				"Dockerfile": "FROM python:3.12-slim\nCMD python server.py\n",
+			},
+			want: &FrameworkInfo{Framework: RuntimeGo, Language: "go", Runtime: RuntimeGo, Source: "go.mod"},
+		},
+		{
+			name:  "nothing recognized",
+			files: map[string]string{"README.md": "hello"},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTree(t, root, tt.files)
+
+			got, err := DetectFramework(root)
+			if err != nil {
+				t.Fatalf("DetectFramework() error = %v", err)
+			}
+			if got != nil {
+				got.sdk = false
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectFramework() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectFrameworkInvalidManifests(t *testing.T) {
+	tests := map[string]map[string]string{
+		"package.json":   {"package.json": `{"dependencies": [}`},
+		"pyproject.toml": {"pyproject.toml": "[project\n"},
+	}
+
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTree(t, root, files)
+
+			if _, err := DetectFramework(root); err == nil {
+				t.Errorf("DetectFramework() succeeded with an invalid %s", name)
+			}
+		})
+	}
+}
@@ -0,0 +1,527 @@
+package filesystem
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// BuiltinSource is the Source of rules that come from the CLI's default excludes
+const BuiltinSource = "built-in"
+
+// IgnoreRule is a single compiled gitignore pattern
+type IgnoreRule struct {
+	Pattern string // the pattern as written
+	Source  string // file the rule was read from, or BuiltinSource
+	Line    int    // 1-based line number in Source, 0 for built-in rules
+	Negate  bool   // pattern started with '!' and re-includes matches
+	DirOnly bool   // pattern ended with '/' and only matches directories
+
+	base     string // slash-separated directory the rule is relative to
+	basename bool   // pattern has no slash and matches the last path element
+	glob     string // pattern with '!', trailing '/' and leading '/' removed
+	regex    *regexp.Regexp
+}
+
+// ParseIgnoreRule compiles one line of a gitignore file. base is the directory
+// containing the file, relative to the scan root ("" for the root itself).
+// It returns nil for blank lines and comments.
+func ParseIgnoreRule(line, base, source string, lineNo int) *IgnoreRule {
+	line = strings.TrimSuffix(line, "\r")
+	line = trimTrailingSpaces(line)
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	rule := &IgnoreRule{
+		Pattern: line,
+		Source:  source,
+		Line:    lineNo,
+		base:    strings.Trim(filepath.ToSlash(base), "/"),
+	}
+
+	pattern := line
+	if strings.HasPrefix(pattern, "!") {
+		rule.Negate = true
+		pattern = pattern[1:]
+	} else if strings.HasPrefix(pattern, `\!`) || strings.HasPrefix(pattern, `\#`) {
+		pattern = pattern[1:]
+	}
+
+	if strings.HasSuffix(pattern, "/") && !strings.HasSuffix(pattern, `\/`) {
+		rule.DirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+
+	if pattern == "" {
+		return nil
+	}
+
+	// A slash at the start or in the middle anchors the pattern to base;
+	// otherwise it matches a file or directory name at any depth
+	if strings.Contains(pattern, "/") {
+		pattern = strings.TrimPrefix(pattern, "/")
+	} else {
+		rule.basename = true
+	}
+
+	regex, err := regexp.Compile("^" + globToRegex(pattern) + "$")
+	if err != nil {
+		return nil
+	}
+	rule.glob = pattern
+	rule.regex = regex
+
+	return rule
+}
+
+// Matches reports whether the rule's pattern matches relPath, a slash-separated
+// path relative to the scan root. Negation is not applied here.
+func (r *IgnoreRule) Matches(relPath string, isDir bool) bool {
+	if r.DirOnly && !isDir {
+		return false
+	}
+
+	relPath = filepath.ToSlash(relPath)
+	if r.base != "" {
+		if !strings.HasPrefix(relPath, r.base+"/") {
+			return false
+		}
+		relPath = strings.TrimPrefix(relPath, r.base+"/")
+	}
+
+	if r.basename {
+		return r.regex.MatchString(path.Base(relPath))
+	}
+	return r.regex.MatchString(relPath)
+}
+
+// couldMatchBeneath reports whether the rule might match some path inside dir.
+// Only anchored patterns qualify: a bare name like "*.js" never reaches into
+// an excluded directory, just as in git.
+func (r *IgnoreRule) couldMatchBeneath(dir string) bool {
+	if r.basename {
+		return false
+	}
+
+	dir = filepath.ToSlash(dir)
+	if r.base != "" {
+		if dir == r.base {
+			return true
+		}
+		if !strings.HasPrefix(dir, r.base+"/") {
+			return strings.HasPrefix(r.base, dir+"/")
+		}
+		dir = strings.TrimPrefix(dir, r.base+"/")
+	}
+
+	segments := strings.Split(r.glob, "/")
+	for i, name := range strings.Split(dir, "/") {
+		if i >= len(segments) {
+			return false
+		}
+		if segments[i] == "**" {
+			return true
+		}
+		matched, err := regexp.MatchString("^"+globToRegex(segments[i])+"$", name)
+		if err != nil || !matched {
+			return false
+		}
+	}
+
+	return len(segments) > len(strings.Split(dir, "/"))
+}
+
+// String describes where the rule came from, e.g. ".gitignore:3: dist/"
+func (r *IgnoreRule) String() string {
+	if r.Line == 0 {
+		return r.Source + ": " + r.Pattern
+	}
+	return r.Source + ":" + strconv.Itoa(r.Line) + ": " + r.Pattern
+}
+
+// IgnoreMatcher decides whether paths are ignored using git's rules: patterns
+// are checked from lowest to highest precedence and the last match wins.
+//
+// Precedence, lowest first: global rules (built-in defaults, then
+// .git/info/exclude), then each kind of per-directory ignore file in the order
+// given to NewIgnoreMatcher, read from the root down to the directory
+// containing the path, then override rules and finally include rules.
+// Per-directory files are loaded lazily, so nested ignore files are honoured
+// wherever they appear.
+type IgnoreMatcher struct {
+	root      string
+	fileNames []string
+
+	mu        sync.Mutex
+	global    []*IgnoreRule
+	perDir    map[ignoreFileKey][]*IgnoreRule
+	overrides []*IgnoreRule
+	includes  []*IgnoreRule
+}
+
+// ignoreFileKey identifies one per-directory ignore file
+type ignoreFileKey struct {
+	dir  string
+	name string
+}
+
+// NewIgnoreMatcher creates a matcher for the tree at root that reads the named
+// per-directory ignore files (e.g. ".gitignore") in every directory. Later
+// names take precedence over earlier ones.
+func NewIgnoreMatcher(root string, fileNames ...string) *IgnoreMatcher {
+	return &IgnoreMatcher{
+		root:      root,
+		fileNames: fileNames,
+		perDir:    make(map[ignoreFileKey][]*IgnoreRule),
+	}
+}
+
+// AddGlobalRules appends rules that apply across the whole tree with higher
+// precedence than global rules added earlier
+func (m *IgnoreMatcher) AddGlobalRules(rules ...*IgnoreRule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.global = appendRules(m.global, rules)
+}
+
+// AddGlobalFile reads a gitignore-format file whose patterns are relative to
+// the root, such as .git/info/exclude. A missing file is not an error.
+func (m *IgnoreMatcher) AddGlobalFile(filePath, source string) error {
+	rules, err := readIgnoreFile(filePath, "", source)
+	if err != nil {
+		return err
+	}
+	m.AddGlobalRules(rules...)
+	return nil
+}
+
+// AddOverrideRules appends rules that take precedence over every ignore file,
+// such as excludes from the command line or project config
+func (m *IgnoreMatcher) AddOverrideRules(rules ...*IgnoreRule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.overrides = appendRules(m.overrides, rules)
+}
+
+// AddIncludeRules appends negated rules that take precedence over everything
+// else. Unlike a '!' line in an ignore file, an include rule can also reach
+// into an excluded directory; see MatchInclude and CouldIncludeBeneath.
+func (m *IgnoreMatcher) AddIncludeRules(rules ...*IgnoreRule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, rule := range rules {
+		if rule != nil {
+			rule.Negate = true
+			m.includes = append(m.includes, rule)
+		}
+	}
+}
+
+// Match returns the highest-precedence rule matching relPath, or nil if no rule
+// matches. The path is ignored if the returned rule is not a negation.
+func (m *IgnoreMatcher) Match(relPath string, isDir bool) *IgnoreRule {
+	relPath = filepath.ToSlash(relPath)
+
+	var match *IgnoreRule
+	for _, rule := range m.rulesFor(relPath) {
+		if rule.Matches(relPath, isDir) {
+			match = rule
+		}
+	}
+	return match
+}
+
+// IsIgnored reports whether relPath is excluded by the rules
+func (m *IgnoreMatcher) IsIgnored(relPath string, isDir bool) bool {
+	rule := m.Match(relPath, isDir)
+	return rule != nil && !rule.Negate
+}
+
+// MatchInclude returns the include rule that explicitly matches relPath, or nil
+func (m *IgnoreMatcher) MatchInclude(relPath string, isDir bool) *IgnoreRule {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.includes) - 1; i >= 0; i-- {
+		if m.includes[i].Matches(relPath, isDir) {
+			return m.includes[i]
+		}
+	}
+	return nil
+}
+
+// CouldIncludeBeneath reports whether an include rule might match a path inside
+// the excluded directory dir, in which case the directory must still be walked
+func (m *IgnoreMatcher) CouldIncludeBeneath(dir string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, rule := range m.includes {
+		if rule.couldMatchBeneath(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// rulesFor collects the rules that can apply to relPath in precedence order
+func (m *IgnoreMatcher) rulesFor(relPath string) []*IgnoreRule {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rules := append([]*IgnoreRule(nil), m.global...)
+
+	// Directories from the root down to the path's parent
+	dirs := []string{""}
+	if dir := path.Dir(relPath); dir != "." {
+		current := ""
+		for _, segment := range strings.Split(dir, "/") {
+			if current == "" {
+				current = segment
+			} else {
+				current += "/" + segment
+			}
+			dirs = append(dirs, current)
+		}
+	}
+
+	for _, name := range m.fileNames {
+		for _, dir := range dirs {
+			rules = append(rules, m.loadFileLocked(dir, name)...)
+		}
+	}
+
+	rules = append(rules, m.overrides...)
+	return append(rules, m.includes...)
+}
+
+// loadFileLocked returns the rules from the ignore file name in dir, reading it
+// on first use. The caller must hold m.mu.
+func (m *IgnoreMatcher) loadFileLocked(dir, name string) []*IgnoreRule {
+	key := ignoreFileKey{dir: dir, name: name}
+	if rules, ok := m.perDir[key]; ok {
+		return rules
+	}
+
+	source := name
+	if dir != "" {
+		source = dir + "/" + name
+	}
+	rules, _ := readIgnoreFile(filepath.Join(m.root, filepath.FromSlash(source)), dir, source)
+
+	m.perDir[key] = rules
+	return rules
+}
+
+// appendRules appends the non-nil rules to dst
+func appendRules(dst, rules []*IgnoreRule) []*IgnoreRule {
+	for _, rule := range rules {
+		if rule != nil {
+			dst = append(dst, rule)
+		}
+	}
+	return dst
+}
+
+// readIgnoreFile parses a gitignore-format file. A missing file yields no rules.
+func readIgnoreFile(filePath, base, source string) ([]*IgnoreRule, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var rules []*IgnoreRule
+	scanner := bufio.NewScanner(file)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if rule := ParseIgnoreRule(scanner.Text(), base, source, lineNo); rule != nil {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, scanner.Err()
+}
+
+// globToRegex translates a gitignore glob to a regular expression.
+// '*' and '?' never match '/', a leading "**/" matches in any directory,
+// a trailing "/**" matches everything inside and "/**/" matches zero or
+// more directories. Any other "**" behaves like '*'.
+func globToRegex(pattern string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				atStart := i == 0 || pattern[i-1] == '/'
+				j := i
+				for j < len(pattern) && pattern[j] == '*' {
+					j++
+				}
+				atEnd := j == len(pattern) || pattern[j] == '/'
+
+				if atStart && atEnd {
+					switch {
+					case j == len(pattern):
+						// "/**" at the end or a lone "**": everything
+						sb.WriteString(".*")
+					default:
+						// "**/" : zero or more directories
+						sb.WriteString("(?:.*/)?")
+						j++ // consume the slash
+					}
+					i = j - 1
+					continue
+				}
+				// Not a path-segment "**": treat as a single star
+				i = j - 1
+			}
+			sb.WriteString("[^/]*")
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := closingBracket(pattern, i)
+			if end < 0 {
+				// git's wildmatch gives up on an unclosed class, so the
+				// pattern never matches
+				return `[^\x00-\x{10FFFF}]`
+			}
+			// Like '*' and '?', a class never matches '/'
+			class := pattern[i+1 : end]
+			negated := strings.HasPrefix(class, "!") || strings.HasPrefix(class, "^")
+			if negated {
+				class = class[1:]
+			}
+			class, ok := classToRegex(class)
+			if !ok {
+				// An unknown [:name:] makes git give up on the pattern
+				return `[^\x00-\x{10FFFF}]`
+			}
+			switch {
+			case negated:
+				sb.WriteString("[^/" + class + "]")
+			case class == "":
+				sb.WriteString(`[^\x00-\x{10FFFF}]`) // matches nothing
+			default:
+				sb.WriteString("[" + class + "]")
+			}
+			i = end
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(pattern[i])))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return sb.String()
+}
+
+// posixClasses maps the POSIX bracket classes git supports to regular
+// expression class contents. Classes that include '/' are spelled out
+// without it.
+var posixClasses = map[string]string{
+	"alnum":  "[:alnum:]",
+	"alpha":  "[:alpha:]",
+	"blank":  "[:blank:]",
+	"cntrl":  "[:cntrl:]",
+	"digit":  "[:digit:]",
+	"lower":  "[:lower:]",
+	"space":  "[:space:]",
+	"upper":  "[:upper:]",
+	"xdigit": "[:xdigit:]",
+	"graph":  `!-.0-~`,
+	"print":  ` -.0-~`,
+	"punct":  "!-.:-@\\[-`{-~",
+}
+
+// classToRegex translates the contents of a bracket expression, without the
+// brackets and negation, to regular expression class contents. It reports
+// false for an unknown [:name:] class.
+func classToRegex(class string) (string, bool) {
+	var sb strings.Builder
+	for i := 0; i < len(class); i++ {
+		if name, end := posixClass(class, i); end >= 0 {
+			regex, ok := posixClasses[name]
+			if !ok {
+				return "", false
+			}
+			sb.WriteString(regex)
+			i = end
+			continue
+		}
+		switch c := class[i]; c {
+		case '/':
+			// Dropped: a class never matches '/'
+		case '\\', '[', ']':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), true
+}
+
+// posixClass returns the name and closing bracket of a "[:name:]" class at i,
+// or an end of -1 if there is none. As in git, the first ']' after "[:" ends
+// it, and without a ':' before that ']' the '[' is an ordinary character.
+func posixClass(pattern string, i int) (string, int) {
+	if i+1 >= len(pattern) || pattern[i] != '[' || pattern[i+1] != ':' {
+		return "", -1
+	}
+	n := strings.IndexByte(pattern[i+2:], ']')
+	if n < 1 || pattern[i+2+n-1] != ':' {
+		return "", -1
+	}
+	return pattern[i+2 : i+2+n-1], i + 2 + n
+}
+
+// closingBracket finds the ']' closing a character class opened at start
+func closingBracket(pattern string, start int) int {
+	i := start + 1
+	if i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^') {
+		i++
+	}
+	// A ']' directly after the opening bracket is literal
+	if i < len(pattern) && pattern[i] == ']' {
+		i++
+	}
+	for ; i < len(pattern); i++ {
+		if _, end := posixClass(pattern, i); end >= 0 {
+			i = end
+			continue
+		}
+		if pattern[i] == ']' {
+			return i
+		}
+	}
+	return -1
+}
+
+// trimTrailingSpaces removes trailing spaces unless they are escaped with a
+// backslash; the escape itself is resolved by globToRegex
+func trimTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	return line
+}
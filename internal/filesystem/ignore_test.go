@@ -0,0 +1,361 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeTree creates files under root; paths are slash-separated
+func writeTree(t testing.TB, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// The expectations follow what 'git check-ignore' reports for the same
+// patterns and paths
+func TestIgnoreRuleMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		base    string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		// Basename patterns match at any depth
+		{"basename at root", "*.log", "", "debug.log", false, true},
+		{"basename nested", "*.log", "", "a/b/debug.log", false, true},
+		{"basename no match", "*.log", "", "debug.txt", false, false},
+		{"basename matches directory", "build", "", "src/build", true, true},
+		{"star does not cross slash", "a*c", "", "ab/c", false, false},
+		{"question mark", "file?.txt", "", "file1.txt", false, true},
+		{"question mark not slash", "a?b", "", "a/b", false, false},
+
+		// A slash anchors the pattern to the directory of the ignore file
+		{"leading slash anchors", "/todo.txt", "", "todo.txt", false, true},
+		{"leading slash not nested", "/todo.txt", "", "sub/todo.txt", false, false},
+		{"middle slash anchors", "doc/frotz", "", "doc/frotz", false, true},
+		{"middle slash not nested", "doc/frotz", "", "a/doc/frotz", false, false},
+		{"anchored to base", "/out", "pkg", "pkg/out", true, true},
+		{"anchored outside base", "/out", "pkg", "out", true, false},
+		{"basename below base", "*.o", "pkg", "pkg/x/y.o", false, true},
+		{"basename outside base", "*.o", "pkg", "lib/y.o", false, false},
+
+		// Directory-only patterns
+		{"trailing slash matches dir", "logs/", "", "logs", true, true},
+		{"trailing slash skips file", "logs/", "", "logs", false, false},
+		{"trailing slash nested dir", "logs/", "", "app/logs", true, true},
+		{"anchored trailing slash", "/dist/", "", "dist", true, true},
+
+		// Leading, middle and trailing "**"
+		{"leading double star root", "**/foo", "", "foo", false, true},
+		{"leading double star nested", "**/foo", "", "a/b/foo", false, true},
+		{"leading double star with dir", "**/foo/bar", "", "x/foo/bar", false, true},
+		{"middle double star zero dirs", "a/**/b", "", "a/b", false, true},
+		{"middle double star one dir", "a/**/b", "", "a/x/b", false, true},
+		{"middle double star many dirs", "a/**/b", "", "a/x/y/z/b", false, true},
+		{"middle double star anchored", "a/**/b", "", "c/a/x/b", false, false},
+		{"trailing double star", "abc/**", "", "abc/x/y", false, true},
+		{"trailing double star not dir itself", "abc/**", "", "abc", true, false},
+		{"double star inside name", "a**b", "", "axxb", false, true},
+		{"double star inside name no slash", "a**b", "", "a/b", false, false},
+
+		// Escapes
+		{"escaped hash", `\#notes`, "", "#notes", false, true},
+		{"escaped bang", `\!important`, "", "!important", false, true},
+		{"escaped trailing space", `space\ `, "", "space ", false, true},
+		{"unescaped trailing space trimmed", "name  ", "", "name", false, true},
+		{"escaped star is literal", `a\*`, "", "a*", false, true},
+		{"escaped star no glob", `a\*`, "", "ab", false, false},
+
+		// Character classes
+		{"class", "file[0-9].txt", "", "file7.txt", false, true},
+		{"class no match", "file[0-9].txt", "", "filex.txt", false, false},
+		{"negated class bang", "file[!0-9]", "", "filex", false, true},
+		{"negated class caret", "file[^0-9]", "", "file1", false, false},
+		{"class with bracket", "[]a]x", "", "]x", false, true},
+		{"class never matches slash", "a[/]b", "", "a/b", false, false},
+		{"unclosed bracket never matches", "a[b", "", "a[b", false, false},
+
+		// POSIX bracket classes
+		{"posix digit", "file[[:digit:]].txt", "", "file7.txt", false, true},
+		{"posix digit no match", "file[[:digit:]].txt", "", "filex.txt", false, false},
+		{"posix class with other characters", "[[:alpha:]_]*.log", "", "_a.log", false, true},
+		{"posix class with other characters no match", "[[:alpha:]_]*.log", "", "1a.log", false, false},
+		{"posix class and range", "x[[:digit:]a-c]", "", "xb", false, true},
+		{"several posix classes", "x[[:upper:][:digit:]]", "", "x5", false, true},
+		{"several posix classes no match", "x[[:upper:][:digit:]]", "", "xa", false, false},
+		{"negated posix class", "x[![:space:]]", "", "xa", false, true},
+		{"negated posix class no match", "x[![:space:]]", "", "x ", false, false},
+		{"posix xdigit", "x[[:xdigit:]]", "", "xf", false, true},
+		{"posix punct", "x[[:punct:]]", "", "x!", false, true},
+		{"posix punct never matches slash", "x[[:punct:]]y", "", "x/y", false, false},
+		{"posix graph never matches slash", "x[[:graph:]]y", "", "x/y", false, false},
+		{"unknown posix class never matches", "x[[:bogus:]]", "", "xa", false, false},
+		{"unclosed posix class never matches", "x[[:digit:]", "", "x1", false, false},
+		{"colon without class name is literal", "x[[:digit]]", "", "xd]", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := ParseIgnoreRule(tt.pattern, tt.base, ".gitignore", 1)
+			if rule == nil {
+				t.Fatalf("ParseIgnoreRule(%q) = nil", tt.pattern)
+			}
+			if got := rule.Matches(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("%q.Matches(%q, dir=%v) = %v, want %v", tt.pattern, tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIgnoreRule(t *testing.T) {
+	tests := []struct {
+		line    string
+		wantNil bool
+		negate  bool
+		dirOnly bool
+	}{
+		{line: "", wantNil: true},
+		{line: "   ", wantNil: true},
+		{line: "# comment", wantNil: true},
+		{line: "/", wantNil: true},
+		{line: "!", wantNil: true},
+		{line: `\#file`},
+		{line: "!keep.log", negate: true},
+		{line: `\!keep.log`},
+		{line: "out/", dirOnly: true},
+		{line: "!out/", negate: true, dirOnly: true},
+		{line: "file\r"},
+	}
+
+	for _, tt := range tests {
+		rule := ParseIgnoreRule(tt.line, "", ".gitignore", 1)
+		if tt.wantNil {
+			if rule != nil {
+				t.Errorf("ParseIgnoreRule(%q) = %v, want nil", tt.line, rule)
+			}
+			continue
+		}
+		if rule == nil {
+			t.Errorf("ParseIgnoreRule(%q) = nil", tt.line)
+			continue
+		}
+		if rule.Negate != tt.negate || rule.DirOnly != tt.dirOnly {
+			t.Errorf("ParseIgnoreRule(%q): Negate=%v DirOnly=%v, want %v %v",
+				tt.line, rule.Negate, rule.DirOnly, tt.negate, tt.dirOnly)
+		}
+	}
+}
+
+func TestIgnoreMatcher(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		global  []string // built-in rules, lowest precedence
+		path    string
+		isDir   bool
+		ignored bool
+	}{
+		{
+			name:    "negation re-includes",
+			files:   map[string]string{".gitignore": "*.log\n!keep.log\n"},
+			path:    "keep.log",
+			ignored: false,
+		},
+		{
+			name:    "last match wins",
+			files:   map[string]string{".gitignore": "!keep.log\n*.log\n"},
+			path:    "keep.log",
+			ignored: true,
+		},
+		{
+			name:    "negation of other files keeps them ignored",
+			files:   map[string]string{".gitignore": "*.log\n!keep.log\n"},
+			path:    "other.log",
+			ignored: true,
+		},
+		{
+			name:    "ignore file re-includes built-in",
+			files:   map[string]string{".gitignore": "!dist/\n"},
+			global:  []string{"dist"},
+			path:    "dist",
+			isDir:   true,
+			ignored: false,
+		},
+		{
+			name:    "nested gitignore overrides parent",
+			files:   map[string]string{".gitignore": "*.txt\n", "sub/.gitignore": "!notes.txt\n"},
+			path:    "sub/notes.txt",
+			ignored: false,
+		},
+		{
+			name:    "nested gitignore only applies beneath it",
+			files:   map[string]string{".gitignore": "*.txt\n", "sub/.gitignore": "!notes.txt\n"},
+			path:    "notes.txt",
+			ignored: true,
+		},
+		{
+			name:    "nested anchored pattern is relative to its directory",
+			files:   map[string]string{"sub/.gitignore": "/gen\n"},
+			path:    "sub/gen",
+			isDir:   true,
+			ignored: true,
+		},
+		{
+			name:    "nested anchored pattern not at root",
+			files:   map[string]string{"sub/.gitignore": "/gen\n"},
+			path:    "gen",
+			isDir:   true,
+			ignored: false,
+		},
+		{
+			name:    "parent gitignore overridden by deeper negation",
+			files:   map[string]string{".gitignore": "!a/b/x.bin\n", "a/.gitignore": "*.bin\n", "a/b/.gitignore": "!x.bin\n"},
+			path:    "a/b/x.bin",
+			ignored: false,
+		},
+		{
+			name:    "leanmcpignore overrides gitignore",
+			files:   map[string]string{".gitignore": "*.sql\n", ".leanmcpignore": "!schema.sql\n"},
+			path:    "schema.sql",
+			ignored: false,
+		},
+		{
+			name:    "gitignore deeper does not beat leanmcpignore",
+			files:   map[string]string{".leanmcpignore": "fixtures/\n", "test/.gitignore": "!fixtures/\n"},
+			path:    "test/fixtures",
+			isDir:   true,
+			ignored: true,
+		},
+		{
+			name:    "info exclude applies",
+			files:   map[string]string{".git/info/exclude": "secret.txt\n"},
+			path:    "secret.txt",
+			ignored: true,
+		},
+		{
+			name:    "gitignore overrides info exclude",
+			files:   map[string]string{".git/info/exclude": "*.dat\n", ".gitignore": "!keep.dat\n"},
+			path:    "keep.dat",
+			ignored: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTree(t, root, tt.files)
+
+			matcher := NewIgnoreMatcher(root, ".gitignore", ".leanmcpignore")
+			for _, pattern := range tt.global {
+				matcher.AddGlobalRules(ParseIgnoreRule(pattern, "", BuiltinSource, 0))
+			}
+			if err := matcher.AddGlobalFile(filepath.Join(root, ".git", "info", "exclude"), ".git/info/exclude"); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := matcher.IsIgnored(tt.path, tt.isDir); got != tt.ignored {
+				rule := matcher.Match(tt.path, tt.isDir)
+				t.Errorf("IsIgnored(%q) = %v, want %v (matched %v)", tt.path, got, tt.ignored, rule)
+			}
+		})
+	}
+}
+
+// scannedPaths returns the relative paths of the files a scan of root finds
+func scannedPaths(t *testing.T, root string, options ScanOptions) []string {
+	t.Helper()
+	files, _, err := NewDirectoryScannerWithOptions(root, options).ScanDirectory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, file := range files {
+		if !file.IsDir {
+			paths = append(paths, filepath.ToSlash(file.RelPath))
+		}
+	}
+	return paths
+}
+
+func TestScanDirectoryIgnoreRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		options ScanOptions
+		want    []string
+	}{
+		{
+			name: "file cannot be re-included when its directory is excluded",
+			files: map[string]string{
+				".gitignore":     "logs/\n!logs/keep.log\n",
+				"logs/keep.log":  "x",
+				"logs/other.log": "x",
+				"main.go":        "x",
+			},
+			want: []string{".gitignore", "main.go"},
+		},
+		{
+			name: "re-include works when the directory contents are excluded instead",
+			files: map[string]string{
+				".gitignore":    "data/*\n!data/keep.csv\n",
+				"data/keep.csv": "x",
+				"data/drop.csv": "x",
+			},
+			want: []string{".gitignore", "data/keep.csv"},
+		},
+		{
+			name: "nested gitignore cannot re-include from excluded parent",
+			files: map[string]string{
+				".gitignore":        "vendor/\n",
+				"vendor/.gitignore": "!*\n",
+				"vendor/lib/a.go":   "x",
+				"app.go":            "x",
+			},
+			want: []string{".gitignore", "app.go"},
+		},
+		{
+			name: "info exclude and nested gitignore",
+			files: map[string]string{
+				".git/info/exclude": "*.bak\n",
+				"src/.gitignore":    "!important.bak\n",
+				"src/important.bak": "x",
+				"src/old.bak":       "x",
+				"top.bak":           "x",
+			},
+			want: []string{"src/.gitignore", "src/important.bak"},
+		},
+		{
+			name: "include option reaches into excluded directory",
+			files: map[string]string{
+				".gitignore":   "dist/\n",
+				"dist/app.js":  "x",
+				"dist/app.map": "x",
+			},
+			options: ScanOptions{Include: NewPatterns("--include", []string{"dist/app.js"})},
+			want:    []string{".gitignore", "dist/app.js"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTree(t, root, tt.files)
+
+			if got := scannedPaths(t, root, tt.options); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scanned %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package filesystem
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ManifestEntry describes one file of a project by content hash. For a
+// symlink the contents are the link target.
+type ManifestEntry struct {
+	Path    string `json:"path"` // slash-separated path relative to the project root
+	Size    int64  `json:"size"`
+	Mode    uint32 `json:"mode"`              // normalized permissions: 0644, 0755, or 0777 for symlinks
+	SHA256  string `json:"sha256"`            // hex SHA-256 of the contents
+	Symlink string `json:"symlink,omitempty"` // link target, set for symlinks
+}
+
+// Manifest lists every file of a project, sorted by path
+type Manifest struct {
+	Files []ManifestEntry `json:"files"`
+}
+
+// TotalSize returns the combined size of all files
+func (m *Manifest) TotalSize() int64 {
+	var total int64
+	for _, file := range m.Files {
+		total += file.Size
+	}
+	return total
+}
+
+// UniqueBlobs returns one entry per distinct content hash, in path order
+func (m *Manifest) UniqueBlobs() []ManifestEntry {
+	seen := make(map[string]bool, len(m.Files))
+	var blobs []ManifestEntry
+	for _, file := range m.Files {
+		if !seen[file.SHA256] {
+			seen[file.SHA256] = true
+			blobs = append(blobs, file)
+		}
+	}
+	return blobs
+}
+
+// Checksum returns a hex SHA-256 of the manifest. It changes whenever a path,
+// mode or file's contents change, so unchanged sources can be recognized
+// without building an archive.
+func (m *Manifest) Checksum() string {
+	data, _ := json.Marshal(m)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ManifestResult represents the result of hashing a project without
+// archiving it
+type ManifestResult struct {
+	Manifest  *Manifest
+	FileCount int
+	TotalSize int64
+	Warnings  []ScanWarning // paths skipped while scanning, e.g. sockets
+}
+
+// CreateManifest scans the project directory and hashes every file without
+// building an archive. The entries are the same as those CreateZip records
+// for unchanged sources.
+func (pz *ProjectZipper) CreateManifest() (*ManifestResult, error) {
+	files, stats, err := pz.scanner.GetFileList()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan directory: %w", err)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files found to archive (directory might be empty or all files are ignored)")
+	}
+
+	files = sortForArchive(files)
+	entries := make([]ManifestEntry, len(files))
+	errs := make([]error, len(files))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workerCount(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				entries[index], errs[index] = hashFile(files[index])
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash file %s: %w", files[i].RelPath, err)
+		}
+	}
+
+	result := &ManifestResult{
+		Manifest:  &Manifest{Files: entries},
+		FileCount: stats.TotalFiles,
+		TotalSize: stats.TotalSize,
+		Warnings:  pz.scanner.Warnings(),
+	}
+
+	return result, nil
+}
+
+// hashFile returns the manifest entry of a single file, reading it in full
+func hashFile(file FileInfo) (ManifestEntry, error) {
+	name := archiveName(file.RelPath)
+
+	if file.IsSymlink() {
+		return symlinkEntry(name, file.LinkTarget), nil
+	}
+
+	sourceFile, err := os.Open(file.Path)
+	if err != nil {
+		return ManifestEntry{}, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer sourceFile.Close()
+
+	info, err := sourceFile.Stat()
+	if err != nil {
+		return ManifestEntry{}, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, sourceFile)
+	if err != nil {
+		return ManifestEntry{}, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	entry := ManifestEntry{
+		Path:   name,
+		Size:   size,
+		Mode:   uint32(normalizedMode(info.Mode())),
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}
+
+	return entry, nil
+}
+
+// symlinkEntry returns the manifest entry of a symlink, whose contents are
+// its slash-separated target
+func symlinkEntry(name, target string) ManifestEntry {
+	target = filepath.ToSlash(target)
+	hash := sha256.Sum256([]byte(target))
+	return ManifestEntry{
+		Path:    name,
+		Size:    int64(len(target)),
+		Mode:    0777,
+		SHA256:  hex.EncodeToString(hash[:]),
+		Symlink: target,
+	}
+}
@@ -1,11 +1,12 @@
 package filesystem
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 )
 
 // FileInfo represents information about a file
@@ -15,6 +16,54 @@ type FileInfo struct {
 	Size     int64
 	IsDir    bool
 	Mode     os.FileMode
+	// LinkTarget is the target of a symlink stored as a link (SymlinksPreserve)
+	LinkTarget string
+}
+
+// IsSymlink reports whether the file is a symlink stored as a link
+func (f FileInfo) IsSymlink() bool {
+	return f.LinkTarget != ""
+}
+
+// ScanWarning is a path that was left out for a reason other than the ignore
+// rules, e.g. a socket or a broken symlink
+type ScanWarning struct {
+	RelPath string
+	Reason  string
+}
+
+// String formats the warning as "path: reason"
+func (w ScanWarning) String() string {
+	return w.RelPath + ": " + w.Reason
+}
+
+// SymlinkPolicy controls how symlinks inside the project are packaged
+type SymlinkPolicy string
+
+// Supported symlink policies
+const (
+	// SymlinksPreserve stores links as symlink entries with their target
+	SymlinksPreserve SymlinkPolicy = "preserve"
+	// SymlinksFollow packages the files and directories links point to
+	SymlinksFollow SymlinkPolicy = "follow"
+	// SymlinksSkip leaves links out
+	SymlinksSkip SymlinkPolicy = "skip"
+)
+
+// DefaultSymlinkPolicy is used when no policy is configured
+const DefaultSymlinkPolicy = SymlinksPreserve
+
+// ParseSymlinkPolicy validates a --symlinks value. An empty value selects
+// DefaultSymlinkPolicy.
+func ParseSymlinkPolicy(value string) (SymlinkPolicy, error) {
+	switch policy := SymlinkPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return DefaultSymlinkPolicy, nil
+	case SymlinksPreserve, SymlinksFollow, SymlinksSkip:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid symlink policy %q (use follow, preserve or skip)", value)
+	}
 }
 
 // FileStats represents statistics about scanned files
@@ -24,19 +73,59 @@ type FileStats struct {
 	TotalDirs  int
 }
 
+// Pattern is a gitignore-style pattern supplied outside of an ignore file
+type Pattern struct {
+	Value  string // the pattern, e.g. "dist/" or "*.map"
+	Source string // where it was configured, e.g. "--include"
+}
+
+// NewPatterns labels each value with the same source
+func NewPatterns(source string, values []string) []Pattern {
+	patterns := make([]Pattern, 0, len(values))
+	for _, value := range values {
+		patterns = append(patterns, Pattern{Value: value, Source: source})
+	}
+	return patterns
+}
+
+// ScanOptions adds user-supplied rules on top of the ignore files
+type ScanOptions struct {
+	// Exclude patterns take precedence over .gitignore and .leanmcpignore
+	Exclude []Pattern
+	// Include patterns take precedence over everything, including the
+	// built-in defaults, and can reach into excluded directories
+	Include []Pattern
+	// Symlinks selects how links are handled; empty means DefaultSymlinkPolicy
+	Symlinks SymlinkPolicy
+}
+
 // DirectoryScanner scans directories and respects ignore patterns
 type DirectoryScanner struct {
 	rootPath        string
-	gitignoreRules  []string
 	excludePatterns []string
+	options         ScanOptions
+	matcher         *IgnoreMatcher
+	warnings        []ScanWarning
 }
 
 // NewDirectoryScanner creates a new directory scanner
 func NewDirectoryScanner(rootPath string) *DirectoryScanner {
+	return NewDirectoryScannerWithOptions(rootPath, ScanOptions{})
+}
+
+// NewDirectoryScannerWithOptions creates a directory scanner with extra
+// include and exclude patterns
+func NewDirectoryScannerWithOptions(rootPath string, options ScanOptions) *DirectoryScanner {
+	if options.Symlinks == "" {
+		options.Symlinks = DefaultSymlinkPolicy
+	}
+	
 	scanner := &DirectoryScanner{
 		rootPath: rootPath,
+		options:  options,
 		excludePatterns: []string{
 			".git",
+			".leanmcp",
 			".DS_Store",
 			"*.log",
 			"node_modules",
@@ -58,156 +147,374 @@ func NewDirectoryScanner(rootPath string) *DirectoryScanner {
 		},
 	}
 	
-	// Load .gitignore rules
-	scanner.loadGitignoreRules()
+	// Load built-in excludes, ignore files and user patterns
+	scanner.loadIgnoreRules()
 	
 	return scanner
 }
 
-// loadGitignoreRules loads patterns from .gitignore file
-func (ds *DirectoryScanner) loadGitignoreRules() {
-	gitignorePath := filepath.Join(ds.rootPath, ".gitignore")
+// loadIgnoreRules builds the ignore matcher. Built-in excludes have the lowest
+// precedence, so an ignore file can re-include one of them with a '!' rule.
+// .leanmcpignore files take precedence over .gitignore files, user excludes
+// over both, and user includes over everything.
+func (ds *DirectoryScanner) loadIgnoreRules() {
+	ds.matcher = NewIgnoreMatcher(ds.rootPath, ".gitignore", ".leanmcpignore")
 	
-	file, err := os.Open(gitignorePath)
-	if err != nil {
-		// No .gitignore file, that's okay
-		return
+	for _, pattern := range ds.excludePatterns {
+		ds.matcher.AddGlobalRules(ParseIgnoreRule(pattern, "", BuiltinSource, 0))
 	}
-	defer file.Close()
 	
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		
-		// Skip empty lines and comments
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		
-		ds.gitignoreRules = append(ds.gitignoreRules, line)
+	excludePath := filepath.Join(ds.rootPath, ".git", "info", "exclude")
+	_ = ds.matcher.AddGlobalFile(excludePath, ".git/info/exclude")
+	
+	for _, pattern := range ds.options.Exclude {
+		ds.matcher.AddOverrideRules(ParseIgnoreRule(pattern.Value, "", pattern.Source, 0))
+	}
+	
+	for _, pattern := range ds.options.Include {
+		value := strings.TrimPrefix(pattern.Value, "!")
+		ds.matcher.AddIncludeRules(ParseIgnoreRule(value, "", pattern.Source, 0))
 	}
 }
 
-// ScanDirectory recursively scans a directory and returns file information
+// ScanDirectory recursively scans a directory and returns file information.
+// Directories are read concurrently by a bounded pool of workers, but the
+// result is always in the same depth-first, name-sorted order. Symlinks are
+// handled according to the scan's SymlinkPolicy; sockets, FIFOs and device
+// files are skipped and reported by Warnings.
 func (ds *DirectoryScanner) ScanDirectory() ([]FileInfo, FileStats, error) {
-	var files []FileInfo
-	stats := FileStats{}
+	walk := &scanWalk{
+		scanner: ds,
+		sem:     make(chan struct{}, workerCount()),
+	}
+	ds.warnings = nil
 	
-	err := filepath.Walk(ds.rootPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		
-		// Get relative path from root
-		relPath, err := filepath.Rel(ds.rootPath, path)
+	var ancestors *realPathChain
+	if ds.options.Symlinks == SymlinksFollow {
+		realRoot, err := filepath.EvalSymlinks(ds.rootPath)
 		if err != nil {
-			return err
-		}
-		
-		// Skip root directory itself
-		if relPath == "." {
-			return nil
-		}
-		
-		// Check if this path should be ignored
-		if ds.shouldIgnore(relPath, info.IsDir()) {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-		
-		fileInfo := FileInfo{
-			Path:    path,
-			RelPath: relPath,
-			Size:    info.Size(),
-			IsDir:   info.IsDir(),
-			Mode:    info.Mode(),
-		}
-		
-		files = append(files, fileInfo)
-		
-		if info.IsDir() {
-			stats.TotalDirs++
-		} else {
-			stats.TotalFiles++
-			stats.TotalSize += info.Size()
+			return nil, FileStats{}, fmt.Errorf("failed to scan directory: %w", err)
 		}
-		
-		return nil
-	})
+		ancestors = &realPathChain{path: realRoot}
+		walk.realRoot = realRoot
+	}
 	
-	if err != nil {
-		return nil, stats, fmt.Errorf("failed to scan directory: %w", err)
+	result := walk.dir(ds.rootPath, "", false, ancestors)
+	ds.warnings = result.warnings
+	if result.err != nil {
+		return nil, result.stats, fmt.Errorf("failed to scan directory: %w", result.err)
 	}
 	
-	return files, stats, nil
+	return result.files, result.stats, nil
 }
 
-// shouldIgnore checks if a path should be ignored based on patterns
-func (ds *DirectoryScanner) shouldIgnore(relPath string, isDir bool) bool {
-	// Check exclude patterns
-	for _, pattern := range ds.excludePatterns {
-		if ds.matchPattern(pattern, relPath, isDir) {
+// Warnings returns the paths the last scan skipped for reasons other than the
+// ignore rules, such as special files, broken links, symlink cycles and links
+// that lead outside the project
+func (ds *DirectoryScanner) Warnings() []ScanWarning {
+	return ds.warnings
+}
+
+// workerCount is the number of directories scanned, or files read and
+// compressed, at the same time
+func workerCount() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+// scanWalk holds the state of a single ScanDirectory call
+type scanWalk struct {
+	scanner *DirectoryScanner
+	// sem holds a token for every directory being read on its own goroutine
+	sem chan struct{}
+	// realRoot is the root with links resolved, set when following links
+	realRoot string
+}
+
+// insideRoot reports whether the real path lies within the project root
+func (w *scanWalk) insideRoot(realPath string) bool {
+	rel, err := filepath.Rel(w.realRoot, realPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// scanResult is what was found in part of the tree, in walk order
+type scanResult struct {
+	files    []FileInfo
+	stats    FileStats
+	warnings []ScanWarning
+	err      error
+}
+
+// add appends other to the result, keeping the first error
+func (r *scanResult) add(other *scanResult) {
+	r.files = append(r.files, other.files...)
+	r.warnings = append(r.warnings, other.warnings...)
+	r.stats.TotalFiles += other.stats.TotalFiles
+	r.stats.TotalSize += other.stats.TotalSize
+	r.stats.TotalDirs += other.stats.TotalDirs
+	if r.err == nil {
+		r.err = other.err
+	}
+}
+
+// warn records a path that was skipped
+func (r *scanResult) warn(relPath, reason string) {
+	r.warnings = append(r.warnings, ScanWarning{RelPath: relPath, Reason: reason})
+}
+
+// realPathChain lists the real paths of the directories from the root down to
+// the one being walked. It is used to detect symlink cycles when following
+// links, and is shared read-only between goroutines.
+type realPathChain struct {
+	path   string
+	parent *realPathChain
+}
+
+// contains reports whether path is in the chain
+func (c *realPathChain) contains(path string) bool {
+	for ; c != nil; c = c.parent {
+		if c.path == path {
 			return true
 		}
 	}
+	return false
+}
+
+// dir walks the directory at path, whose path relative to the root is relDir.
+// excluded is set for an excluded directory that is still walked because an
+// include pattern may match something inside it. Subdirectories are walked
+// on new goroutines while workers are free and inline otherwise.
+func (w *scanWalk) dir(path, relDir string, excluded bool, ancestors *realPathChain) *scanResult {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return &scanResult{err: err}
+	}
 	
-	// Check .gitignore rules
-	for _, rule := range ds.gitignoreRules {
-		if ds.matchGitignoreRule(rule, relPath, isDir) {
-			return true
+	// One part per entry, merged in order once every subdirectory is done
+	parts := make([]*scanResult, len(entries))
+	var wg sync.WaitGroup
+	
+	for i, entry := range entries {
+		parts[i] = &scanResult{}
+		if !w.entry(parts[i], path, relDir, entry.Name(), excluded, ancestors, &wg) {
+			break
 		}
 	}
+	wg.Wait()
 	
-	return false
+	result := &scanResult{}
+	for _, part := range parts {
+		if part != nil {
+			result.add(part)
+		}
+	}
+	return result
 }
 
-// matchPattern checks if a path matches a simple pattern
-func (ds *DirectoryScanner) matchPattern(pattern, path string, isDir bool) bool {
-	// Handle wildcards
-	if strings.Contains(pattern, "*") {
-		matched, _ := filepath.Match(pattern, filepath.Base(path))
-		return matched
+// entry adds one directory entry, and everything beneath it for a directory,
+// to part. It returns false if the walk should stop because of an error.
+func (w *scanWalk) entry(part *scanResult, dirPath, relDir, name string, excluded bool, ancestors *realPathChain, wg *sync.WaitGroup) bool {
+	entryPath := filepath.Join(dirPath, name)
+	relPath := filepath.Join(relDir, name)
+	
+	info, err := os.Lstat(entryPath)
+	if err != nil {
+		part.err = err
+		return false
+	}
+	
+	fileInfo := FileInfo{
+		Path:    entryPath,
+		RelPath: relPath,
+		Size:    info.Size(),
+		IsDir:   info.IsDir(),
+		Mode:    info.Mode(),
+	}
+	
+	if info.Mode()&os.ModeSymlink != 0 {
+		switch w.scanner.options.Symlinks {
+		case SymlinksSkip:
+			return true
+		case SymlinksFollow:
+			target, err := os.Stat(entryPath)
+			if err != nil {
+				part.warn(relPath, "broken symlink")
+				return true
+			}
+			realTarget, err := filepath.EvalSymlinks(entryPath)
+			if err != nil {
+				part.warn(relPath, "broken symlink")
+				return true
+			}
+			if !w.insideRoot(realTarget) {
+				part.warn(relPath, "symlink target outside the project")
+				return true
+			}
+			fileInfo.Size = target.Size()
+			fileInfo.IsDir = target.IsDir()
+			fileInfo.Mode = target.Mode()
+		default:
+			fileInfo.LinkTarget, err = os.Readlink(entryPath)
+			if err != nil {
+				part.err = err
+				return false
+			}
+		}
 	}
 	
-	// Exact match for directories
-	if isDir && pattern == filepath.Base(path) {
+	// Check if this path should be ignored
+	ignored, _ := w.scanner.decide(relPath, fileInfo.IsDir, excluded)
+	if ignored && !(fileInfo.IsDir && w.scanner.matcher.CouldIncludeBeneath(filepath.ToSlash(relPath))) {
 		return true
 	}
 	
-	// Check if path starts with pattern (for directory patterns)
-	if strings.HasPrefix(path, pattern+string(filepath.Separator)) {
+	if !fileInfo.IsDir && fileInfo.LinkTarget == "" && !fileInfo.Mode.IsRegular() {
+		part.warn(relPath, specialFileKind(fileInfo.Mode))
+		return true
+	}
+	
+	if !fileInfo.IsDir {
+		if !ignored {
+			part.files = append(part.files, fileInfo)
+			part.stats.TotalFiles++
+			part.stats.TotalSize += fileInfo.Size
+		}
 		return true
 	}
 	
-	// Exact match
-	return pattern == path || pattern == filepath.Base(path)
+	// Guard against links that lead back into a directory being walked
+	if ancestors != nil {
+		realPath, err := filepath.EvalSymlinks(entryPath)
+		if err != nil {
+			part.err = err
+			return false
+		}
+		if ancestors.contains(realPath) {
+			part.warn(relPath, "symlink cycle")
+			return true
+		}
+		ancestors = &realPathChain{path: realPath, parent: ancestors}
+	}
+	
+	if !ignored {
+		part.files = append(part.files, fileInfo)
+		part.stats.TotalDirs++
+	}
+	
+	select {
+	case w.sem <- struct{}{}:
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-w.sem }()
+			part.add(w.dir(entryPath, relPath, ignored, ancestors))
+		}()
+	default:
+		part.add(w.dir(entryPath, relPath, ignored, ancestors))
+	}
+	
+	return true
 }
 
-// matchGitignoreRule checks if a path matches a .gitignore rule
-func (ds *DirectoryScanner) matchGitignoreRule(rule, path string, isDir bool) bool {
-	// Handle negation rules (starting with !)
-	if strings.HasPrefix(rule, "!") {
-		return false // Negation rules are complex, skip for now
+// specialFileKind names the type of a non-regular file
+func specialFileKind(mode os.FileMode) string {
+	switch {
+	case mode&os.ModeSocket != 0:
+		return "socket"
+	case mode&os.ModeNamedPipe != 0:
+		return "named pipe"
+	case mode&os.ModeCharDevice != 0:
+		return "character device"
+	case mode&os.ModeDevice != 0:
+		return "device file"
+	default:
+		return "special file"
 	}
+}
+
+// decide reports whether a path is ignored and the rule that decided it.
+// Inside an excluded directory only explicit includes survive.
+func (ds *DirectoryScanner) decide(relPath string, isDir, parentExcluded bool) (bool, *IgnoreRule) {
+	relPath = filepath.ToSlash(relPath)
 	
-	// Handle directory-only rules (ending with /)
-	if strings.HasSuffix(rule, "/") {
-		if !isDir {
-			return false
+	if parentExcluded {
+		if rule := ds.matcher.MatchInclude(relPath, isDir); rule != nil {
+			return false, rule
+		}
+		return true, nil
+	}
+	
+	rule := ds.matcher.Match(relPath, isDir)
+	return rule != nil && !rule.Negate, rule
+}
+
+// IgnoreResult explains whether a path would be uploaded
+type IgnoreResult struct {
+	Path    string
+	IsDir   bool
+	Ignored bool
+	// Rule is the last rule that matched the path, or the rule that excluded
+	// Parent when the path is hidden by an excluded directory. It can be a
+	// negation that re-included the path or one of its ancestors, and is nil
+	// if no rule applied.
+	Rule *IgnoreRule
+	// Parent is the excluded ancestor directory that hides the path, if any
+	Parent string
+}
+
+// CheckIgnore explains how the ignore rules treat relPath. The path does not
+// have to exist; a trailing slash marks it as a directory.
+func (ds *DirectoryScanner) CheckIgnore(relPath string) (*IgnoreResult, error) {
+	isDir := strings.HasSuffix(filepath.ToSlash(relPath), "/")
+	relPath = filepath.ToSlash(filepath.Clean(relPath))
+	if relPath == "." || relPath == ".." || strings.HasPrefix(relPath, "../") || filepath.IsAbs(relPath) {
+		return nil, fmt.Errorf("path must be inside the project directory: %s", relPath)
+	}
+	
+	if info, err := os.Stat(filepath.Join(ds.rootPath, filepath.FromSlash(relPath))); err == nil {
+		isDir = info.IsDir()
+	}
+	
+	result := &IgnoreResult{Path: relPath, IsDir: isDir}
+	
+	// Walk the ancestors the way ScanDirectory would
+	var excludedBy, includedBy *IgnoreRule
+	excludedDir := ""
+	segments := strings.Split(relPath, "/")
+	for i := 1; i < len(segments); i++ {
+		dir := strings.Join(segments[:i], "/")
+		ignored, rule := ds.decide(dir, true, excludedDir != "")
+		if !ignored {
+			if rule != nil && rule.Negate {
+				includedBy = rule
+			}
+			excludedBy, excludedDir = nil, ""
+			continue
+		}
+		
+		if excludedDir == "" {
+			excludedBy, excludedDir = rule, dir
+		}
+		if !ds.matcher.CouldIncludeBeneath(dir) {
+			result.Ignored = true
+			result.Parent = excludedDir
+			result.Rule = excludedBy
+			return result, nil
 		}
-		rule = strings.TrimSuffix(rule, "/")
 	}
 	
-	// Handle absolute paths (starting with /)
-	if strings.HasPrefix(rule, "/") {
-		rule = strings.TrimPrefix(rule, "/")
-		return ds.matchPattern(rule, path, isDir)
+	ignored, rule := ds.decide(relPath, isDir, excludedDir != "")
+	result.Ignored = ignored
+	result.Rule = rule
+	switch {
+	case ignored && rule == nil && excludedDir != "":
+		result.Parent = excludedDir
+		result.Rule = excludedBy
+	case !ignored && rule == nil:
+		// Report the rule that re-included an ancestor, if any
+		result.Rule = includedBy
 	}
 	
-	// Handle wildcards and regular patterns
-	return ds.matchPattern(rule, path, isDir) || ds.matchPattern(rule, filepath.Base(path), isDir)
+	return result, nil
 }
 
 // GetFileList returns only non-directory files
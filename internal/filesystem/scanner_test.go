@@ -0,0 +1,67 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestScanDirectoryFollowSymlinks(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "project")
+	writeTree(t, root, map[string]string{
+		"main.go":       "x",
+		"shared/lib.go": "x",
+	})
+	writeTree(t, base, map[string]string{
+		"outside/secret.txt": "x",
+		"outside.txt":        "x",
+	})
+
+	links := map[string]string{
+		"lib":         "shared",
+		"main-link":   "main.go",
+		"escape":      filepath.Join("..", "outside"),
+		"escape.txt":  filepath.Join(base, "outside.txt"),
+		"shared/loop": "..",
+		"broken":      "missing.go",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(name))); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	scanner := NewDirectoryScannerWithOptions(root, ScanOptions{Symlinks: SymlinksFollow})
+	files, _, err := scanner.ScanDirectory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, file := range files {
+		if !file.IsDir {
+			got = append(got, filepath.ToSlash(file.RelPath))
+		}
+	}
+	want := []string{"lib/lib.go", "main-link", "main.go", "shared/lib.go"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("scanned %v, want %v", got, want)
+	}
+
+	warnings := make(map[string]string)
+	for _, warning := range scanner.Warnings() {
+		warnings[filepath.ToSlash(warning.RelPath)] = warning.Reason
+	}
+	wantWarnings := map[string]string{
+		"broken":      "broken symlink",
+		"escape":      "symlink target outside the project",
+		"escape.txt":  "symlink target outside the project",
+		"lib/loop":    "symlink cycle",
+		"shared/loop": "symlink cycle",
+	}
+	if !reflect.DeepEqual(warnings, wantWarnings) {
+		t.Errorf("warnings = %v, want %v", warnings, wantWarnings)
+	}
+}
@@ -0,0 +1,248 @@
+package filesystem
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// secretAllowMarker on a line suppresses findings on that line
+const secretAllowMarker = "leanmcp:allow-secret"
+
+// MaxSecretScanSize is the largest file scanned; bigger files are usually
+// data or build output rather than configuration
+const MaxSecretScanSize = 5 * 1024 * 1024
+
+// SecretRule detects one kind of credential
+type SecretRule struct {
+	Name  string
+	Regex *regexp.Regexp
+	// Group is the submatch holding the secret value (0 for the whole match)
+	Group int
+	// MinEntropy, if set, is the Shannon entropy in bits per character the
+	// value must reach, to tell real keys from placeholders like "changeme"
+	MinEntropy float64
+}
+
+// DefaultSecretRules are the credentials checked before every upload
+var DefaultSecretRules = []SecretRule{
+	{Name: "LeanMCP API key", Regex: regexp.MustCompile(`\bairtrain_[A-Za-z0-9_-]{16,}`)},
+	{Name: "AWS access key ID", Regex: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "AWS secret access key", Regex: regexp.MustCompile(`(?i)aws.{0,20}(?:secret|private).{0,20}?['"=:\s]([A-Za-z0-9/+]{40})\b`), Group: 1, MinEntropy: 4.0},
+	{Name: "Private key", Regex: regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{Name: "GitHub token", Regex: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{60,})\b`)},
+	{Name: "Slack token", Regex: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{Name: "Stripe secret key", Regex: regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}`)},
+	{Name: "Google API key", Regex: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{Name: "OpenAI/Anthropic API key", Regex: regexp.MustCompile(`\bsk-(?:ant-|proj-)?[A-Za-z0-9_-]{32,}`), MinEntropy: 3.5},
+	{Name: "High-entropy secret assignment", Regex: regexp.MustCompile(`(?i)(?:api[_-]?key|secret|token|passw(?:or)?d|access[_-]?key|auth)["']?\s*[:=]\s*["']?([A-Za-z0-9_\-/+=.]{20,})`), Group: 1, MinEntropy: 4.0},
+}
+
+// SecretScanOptions configures which findings are allowed
+type SecretScanOptions struct {
+	// AllowPaths are gitignore-style patterns of files that are not scanned
+	AllowPaths []string
+	// AllowPatterns are regular expressions; a finding whose value matches
+	// one of them is ignored (e.g. a known test key)
+	AllowPatterns []string
+}
+
+// SecretFinding is a possible credential in a project file
+type SecretFinding struct {
+	Path  string `json:"path"`
+	Line  int    `json:"line"`
+	Rule  string `json:"rule"`
+	Match string `json:"match"` // redacted
+}
+
+// SecretScanResult is the outcome of scanning a project for secrets
+type SecretScanResult struct {
+	Findings []SecretFinding
+	// Skipped are the files larger than MaxSecretScanSize, which were not
+	// scanned
+	Skipped []string
+}
+
+// SecretScanner checks files for credentials before they are uploaded
+type SecretScanner struct {
+	rules         []SecretRule
+	allowPaths    []*IgnoreRule
+	allowPatterns []*regexp.Regexp
+}
+
+// NewSecretScanner creates a scanner using DefaultSecretRules
+func NewSecretScanner(options SecretScanOptions) (*SecretScanner, error) {
+	scanner := &SecretScanner{rules: DefaultSecretRules}
+
+	for _, pattern := range options.AllowPaths {
+		if rule := ParseIgnoreRule(pattern, "", "allowPaths", 0); rule != nil {
+			scanner.allowPaths = append(scanner.allowPaths, rule)
+		}
+	}
+
+	for _, pattern := range options.AllowPatterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret allow pattern %q: %w", pattern, err)
+		}
+		scanner.allowPatterns = append(scanner.allowPatterns, regex)
+	}
+
+	return scanner, nil
+}
+
+// ScanFiles checks every file and returns the findings in file order, along
+// with the files too large to scan
+func (s *SecretScanner) ScanFiles(files []FileInfo) (*SecretScanResult, error) {
+	result := &SecretScanResult{}
+	for _, file := range files {
+		if file.IsDir || !file.Mode.IsRegular() {
+			continue
+		}
+
+		relPath := filepath.ToSlash(file.RelPath)
+		if s.pathAllowed(relPath) {
+			continue
+		}
+		if file.Size > MaxSecretScanSize {
+			result.Skipped = append(result.Skipped, relPath)
+			continue
+		}
+
+		fileFindings, err := s.scanFile(file.Path, relPath)
+		if err != nil {
+			return nil, err
+		}
+		result.Findings = append(result.Findings, fileFindings...)
+	}
+	return result, nil
+}
+
+// pathAllowed reports whether the file is excluded from scanning. Patterns
+// matching a parent directory, like "test/fixtures/", cover the files below
+// it; a later negated pattern can still match one of those files.
+func (s *SecretScanner) pathAllowed(relPath string) bool {
+	allowed := false
+	parts := strings.Split(relPath, "/")
+	for i := 1; i <= len(parts); i++ {
+		isDir := i < len(parts)
+		for _, rule := range s.allowPaths {
+			if rule.Matches(strings.Join(parts[:i], "/"), isDir) {
+				allowed = !rule.Negate
+			}
+		}
+	}
+	return allowed
+}
+
+// scanFile checks one file line by line, skipping binary files
+func (s *SecretScanner) scanFile(path, relPath string) ([]SecretFinding, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", relPath, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	head, err := reader.Peek(8000)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil, nil
+	}
+
+	var findings []SecretFinding
+	lines := bufio.NewScanner(reader)
+	lines.Buffer(make([]byte, 64*1024), MaxSecretScanSize)
+	lineNo := 0
+	for lines.Scan() {
+		lineNo++
+		line := lines.Text()
+		if strings.Contains(line, secretAllowMarker) {
+			continue
+		}
+
+		// Specific rules come first; a generic rule matching the same value
+		// on the line is not reported again
+		var found []string
+		for _, rule := range s.rules {
+			for _, match := range rule.Regex.FindAllStringSubmatch(line, -1) {
+				value := match[rule.Group]
+				if rule.MinEntropy > 0 && shannonEntropy(value) < rule.MinEntropy {
+					continue
+				}
+				if s.valueAllowed(value) || overlapsAny(value, found) {
+					continue
+				}
+				found = append(found, value)
+				findings = append(findings, SecretFinding{
+					Path:  relPath,
+					Line:  lineNo,
+					Rule:  rule.Name,
+					Match: redactSecret(value),
+				})
+			}
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", relPath, err)
+	}
+
+	return findings, nil
+}
+
+// valueAllowed reports whether an allow pattern matches the secret value
+func (s *SecretScanner) valueAllowed(value string) bool {
+	for _, regex := range s.allowPatterns {
+		if regex.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// overlapsAny reports whether value contains or is contained in any of values
+func overlapsAny(value string, values []string) bool {
+	for _, other := range values {
+		if strings.Contains(value, other) || strings.Contains(other, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+
+	var entropy float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// redactSecret keeps just enough of a value to recognize it
+func redactSecret(value string) string {
+	if len(value) <= 8 {
+		return strings.Repeat("*", len(value))
+	}
+	return value[:4] + strings.Repeat("*", 8)
+}
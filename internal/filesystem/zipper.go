@@ -1,24 +1,74 @@
 package filesystem
 
 import (
-	"archive/zip"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
-// ZipResult represents the result of a zip operation
+// MaxZipSize is the largest archive that can be uploaded
+const MaxZipSize = 500 * 1024 * 1024 // 500MB limit
+
+// archiveModTime is stored for every entry so that identical sources always
+// produce byte-identical archives (the zip format's earliest date)
+var archiveModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ZipResult represents the result of an archive operation. The archive is written
+// to a temporary file at Path; call Remove when done with it.
 type ZipResult struct {
-	Data      []byte
+	Path      string
+	Format    ArchiveFormat
+	Size      int64
+	Checksum  string // hex SHA-256 of the archive
 	FileCount int
 	TotalSize int64
+	Warnings  []ScanWarning // paths skipped while scanning, e.g. sockets
+}
+
+// Open opens the archive for reading
+func (r *ZipResult) Open() (*os.File, error) {
+	return os.Open(r.Path)
+}
+
+// Remove deletes the archive file
+func (r *ZipResult) Remove() error {
+	err := os.Remove(r.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// limitWriter counts bytes written and fails once the limit is exceeded, so an
+// oversized archive is rejected while it is being built
+type limitWriter struct {
+	w     io.Writer
+	limit int64
+	n     int64
 }
 
-// ProjectZipper handles zipping project files
+func (lw *limitWriter) Write(p []byte) (int, error) {
+	if lw.n+int64(len(p)) > lw.limit {
+		return 0, fmt.Errorf("archive too large: exceeds %d MB", lw.limit/(1024*1024))
+	}
+	n, err := lw.w.Write(p)
+	lw.n += int64(n)
+	return n, err
+}
+
+// ProjectZipper handles archiving project files
 type ProjectZipper struct {
 	scanner *DirectoryScanner
+	// Archive selects the format and compression level; the zero value
+	// writes a zip with default compression
+	Archive ArchiveOptions
 }
 
 // NewProjectZipper creates a new project zipper
@@ -28,7 +78,20 @@ func NewProjectZipper(projectPath string) *ProjectZipper {
 	}
 }
 
-// CreateZip creates a zip file from the project directory
+// NewProjectZipperWithOptions creates a project zipper that applies extra
+// include and exclude patterns
+func NewProjectZipperWithOptions(projectPath string, options ScanOptions) *ProjectZipper {
+	return &ProjectZipper{
+		scanner: NewDirectoryScannerWithOptions(projectPath, options),
+	}
+}
+
+// CreateZip creates an archive of the project directory in the format selected
+// by Archive (zip unless set). The archive is streamed to a temporary file
+// and creation stops as soon as it grows past MaxZipSize. Archives are
+// reproducible: entries are sorted by path and have fixed timestamps and
+// normalized permissions, so unchanged sources with the same Archive options
+// always produce the same Checksum.
 func (pz *ProjectZipper) CreateZip() (*ZipResult, error) {
 	// Scan directory for files
 	files, stats, err := pz.scanner.GetFileList()
@@ -37,84 +100,233 @@ func (pz *ProjectZipper) CreateZip() (*ZipResult, error) {
 	}
 	
 	if len(files) == 0 {
-		return nil, fmt.Errorf("no files found to zip (directory might be empty or all files are ignored)")
+		return nil, fmt.Errorf("no files found to archive (directory might be empty or all files are ignored)")
 	}
 	
-	// Create zip in memory
-	var buf bytes.Buffer
-	zipWriter := zip.NewWriter(&buf)
-	
-	for _, file := range files {
-		err := pz.addFileToZip(zipWriter, file)
-		if err != nil {
-			zipWriter.Close()
-			return nil, fmt.Errorf("failed to add file %s to zip: %w", file.RelPath, err)
-		}
+	format := pz.Archive.format()
+	tempFile, err := os.CreateTemp("", "leanmcp-*"+format.Extension())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
 	}
 	
-	err = zipWriter.Close()
+	hash := sha256.New()
+	size, err := pz.writeArchive(io.MultiWriter(tempFile, hash), files)
+	closeErr := tempFile.Close()
+	if err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write archive: %w", closeErr)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to finalize zip: %w", err)
+		os.Remove(tempFile.Name())
+		return nil, err
 	}
 	
 	result := &ZipResult{
-		Data:      buf.Bytes(),
+		Path:      tempFile.Name(),
+		Format:    format,
+		Size:      size,
+		Checksum:  hex.EncodeToString(hash.Sum(nil)),
 		FileCount: stats.TotalFiles,
 		TotalSize: stats.TotalSize,
+		Warnings:  pz.scanner.Warnings(),
 	}
 	
 	return result, nil
 }
 
-// addFileToZip adds a single file to the zip archive
-func (pz *ProjectZipper) addFileToZip(zipWriter *zip.Writer, file FileInfo) error {
-	// Open source file
+// maxBufferedFileSize is the largest file a worker reads into memory; larger
+// files are streamed into the archive by the writer
+const maxBufferedFileSize = 4 * 1024 * 1024
+
+// preparedFile is a file that a worker has read and, for formats that
+// compress each entry on its own, compressed
+type preparedFile struct {
+	entry      ManifestEntry    // archive path, size and mode
+	streamed   bool             // too large to buffer; the writer reads the file itself
+	content    []byte           // file content, unless streamed or compressed
+	compressed *compressedEntry // set when the archive compresses entries separately
+	err        error
+}
+
+// writeArchive writes the files as an archive in the configured format to w
+// and returns the archive size. Workers read and compress files ahead of the
+// writer, which adds them in path order so the archive does not depend on
+// scheduling.
+func (pz *ProjectZipper) writeArchive(w io.Writer, files []FileInfo) (int64, error) {
+	limited := &limitWriter{w: w, limit: MaxZipSize}
+	archive, err := newArchiveWriter(limited, pz.Archive)
+	if err != nil {
+		return 0, err
+	}
+	compressor, _ := archive.(entryCompressor)
+	
+	files = sortForArchive(files)
+	
+	// At most window files are prepared ahead of the writer, which bounds
+	// memory use to a few buffered files per worker
+	workers := workerCount()
+	window := 2 * workers
+	results := make([]chan preparedFile, len(files))
+	jobs := make(chan int, window)
+	
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				results[index] <- pz.prepareFile(files[index], compressor)
+			}
+		}()
+	}
+	defer wg.Wait()
+	defer close(jobs)
+	
+	next := 0
+	dispatch := func() {
+		if next < len(files) {
+			results[next] = make(chan preparedFile, 1)
+			jobs <- next
+			next++
+		}
+	}
+	for i := 0; i < window; i++ {
+		dispatch()
+	}
+	
+	for i, file := range files {
+		prepared := <-results[i]
+		dispatch()
+		
+		if err := pz.writePrepared(archive, compressor, file, prepared); err != nil {
+			archive.Close()
+			return 0, fmt.Errorf("failed to add file %s to archive: %w", file.RelPath, err)
+		}
+	}
+	
+	err = archive.Close()
+	if err != nil {
+		return 0, fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	
+	return limited.n, nil
+}
+
+// prepareFile does the work for one file that can run in parallel: reading
+// it and, if compressor is set, compressing it. Symlinks only need their
+// target.
+func (pz *ProjectZipper) prepareFile(file FileInfo, compressor entryCompressor) preparedFile {
+	name := archiveName(file.RelPath)
+	
+	if file.IsSymlink() {
+		return preparedFile{entry: symlinkEntry(name, file.LinkTarget)}
+	}
+	
+	if file.Size > maxBufferedFileSize {
+		return preparedFile{streamed: true}
+	}
+	
 	sourceFile, err := os.Open(file.Path)
 	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
+		return preparedFile{err: fmt.Errorf("failed to open file: %w", err)}
 	}
 	defer sourceFile.Close()
 	
-	// Get file info for permissions
 	info, err := sourceFile.Stat()
 	if err != nil {
-		return fmt.Errorf("failed to get file info: %w", err)
+		return preparedFile{err: fmt.Errorf("failed to get file info: %w", err)}
+	}
+	if info.Size() > maxBufferedFileSize {
+		// Grew since the scan
+		return preparedFile{streamed: true}
 	}
 	
-	// Create zip file header
-	header, err := zip.FileInfoHeader(info)
+	content, err := io.ReadAll(sourceFile)
 	if err != nil {
-		return fmt.Errorf("failed to create zip header: %w", err)
+		return preparedFile{err: fmt.Errorf("failed to read file: %w", err)}
 	}
 	
-	// Use forward slashes for zip paths (cross-platform compatibility)
-	header.Name = strings.ReplaceAll(file.RelPath, "\\", "/")
+	prepared := preparedFile{
+		entry: ManifestEntry{
+			Path: name,
+			Size: int64(len(content)),
+			Mode: uint32(normalizedMode(info.Mode())),
+		},
+		content: content,
+	}
+	
+	if compressor != nil {
+		prepared.compressed, prepared.err = compressor.CompressEntry(content)
+		prepared.content = nil
+	}
 	
-	// Set compression method
-	header.Method = zip.Deflate
+	return prepared
+}
+
+// writePrepared adds a prepared file to the archive
+func (pz *ProjectZipper) writePrepared(archive archiveWriter, compressor entryCompressor, file FileInfo, prepared preparedFile) error {
+	entry := prepared.entry
 	
-	// Create file in zip
-	writer, err := zipWriter.CreateHeader(header)
+	switch {
+	case prepared.err != nil:
+		return prepared.err
+	case prepared.streamed:
+		return pz.addFile(archive, file)
+	case entry.Symlink != "":
+		return archive.WriteSymlink(entry.Path, entry.Symlink)
+	case prepared.compressed != nil:
+		return compressor.WriteCompressed(entry.Path, os.FileMode(entry.Mode), prepared.compressed)
+	default:
+		return archive.WriteFile(entry.Path, os.FileMode(entry.Mode), entry.Size, bytes.NewReader(prepared.content))
+	}
+}
+
+// addFile streams a single file into the archive
+func (pz *ProjectZipper) addFile(archive archiveWriter, file FileInfo) error {
+	// Open source file
+	sourceFile, err := os.Open(file.Path)
 	if err != nil {
-		return fmt.Errorf("failed to create zip entry: %w", err)
+		return fmt.Errorf("failed to open file: %w", err)
 	}
+	defer sourceFile.Close()
 	
-	// Copy file content
-	_, err = io.Copy(writer, sourceFile)
+	// Get file info for permissions and size
+	info, err := sourceFile.Stat()
 	if err != nil {
-		return fmt.Errorf("failed to copy file content: %w", err)
+		return fmt.Errorf("failed to get file info: %w", err)
 	}
 	
-	return nil
+	return archive.WriteFile(archiveName(file.RelPath), normalizedMode(info.Mode()), info.Size(), sourceFile)
 }
 
-// ValidateZipSize checks if the zip size is within reasonable limits
-func ValidateZipSize(data []byte) error {
-	const maxSizeMB = 500 // 500MB limit
-	const maxSizeBytes = maxSizeMB * 1024 * 1024
-	
-	if len(data) > maxSizeBytes {
-		return fmt.Errorf("zip file too large: %d bytes (max %d MB)", len(data), maxSizeMB)
+// sortForArchive returns a copy of files sorted by archive path, so the entry
+// order does not depend on the platform
+func sortForArchive(files []FileInfo) []FileInfo {
+	files = append([]FileInfo(nil), files...)
+	sort.Slice(files, func(i, j int) bool {
+		return archiveName(files[i].RelPath) < archiveName(files[j].RelPath)
+	})
+	return files
+}
+
+// archiveName converts a relative path to a zip entry name. Zip paths always
+// use forward slashes (cross-platform compatibility).
+func archiveName(relPath string) string {
+	return strings.ReplaceAll(relPath, "\\", "/")
+}
+
+// normalizedMode maps a file mode to 0755 if any execute bit is set and to
+// 0644 otherwise
+func normalizedMode(mode os.FileMode) os.FileMode {
+	if mode&0111 != 0 {
+		return 0755
+	}
+	return 0644
+}
+
+// ValidateZipSize checks if the archive size is within reasonable limits
+func ValidateZipSize(size int64) error {
+	if size > MaxZipSize {
+		return fmt.Errorf("archive too large: %d bytes (max %d MB)", size, MaxZipSize/(1024*1024))
 	}
 	
 	return nil
@@ -136,16 +348,28 @@ func GetHumanReadableSize(bytes int64) string {
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
-// PreviewFiles returns a preview of files that would be included in the zip
+// PreviewFiles returns a preview of files that would be included in the archive.
+// A limit of zero or less returns every file.
 func (pz *ProjectZipper) PreviewFiles(limit int) ([]FileInfo, FileStats, error) {
 	files, stats, err := pz.scanner.GetFileList()
 	if err != nil {
 		return nil, stats, err
 	}
 	
-	if len(files) > limit {
+	if limit > 0 && len(files) > limit {
 		return files[:limit], stats, nil
 	}
 	
 	return files, stats, nil
 }
+
+// Warnings returns the paths skipped by the last scan, e.g. sockets and
+// broken symlinks
+func (pz *ProjectZipper) Warnings() []ScanWarning {
+	return pz.scanner.Warnings()
+}
+
+// CheckIgnore explains how the zipper's ignore rules treat relPath
+func (pz *ProjectZipper) CheckIgnore(relPath string) (*IgnoreResult, error) {
+	return pz.scanner.CheckIgnore(relPath)
+}
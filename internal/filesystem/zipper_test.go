@@ -0,0 +1,200 @@
+package filesystem
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// generateTree writes a project of n small files spread over nested
+// directories, plus one file too large to be buffered by the workers
+func generateTree(tb testing.TB, root string, n int) {
+	tb.Helper()
+	files := make(map[string]string, n+1)
+	for i := 0; i < n; i++ {
+		dir := fmt.Sprintf("pkg%02d/sub%02d", i%25, (i/25)%8)
+		content := bytes.Repeat([]byte(fmt.Sprintf("line %d of file %d\n", i%7, i)), 20+i%50)
+		files[fmt.Sprintf("%s/file%05d.go", dir, i)] = string(content)
+	}
+
+	large := make([]byte, maxBufferedFileSize+1024)
+	for i := range large {
+		large[i] = byte(i * 31 % 251)
+	}
+	files["assets/large.bin"] = string(large)
+
+	writeTree(tb, root, files)
+}
+
+// readArchive creates an archive of root and returns its bytes
+func readArchive(tb testing.TB, root string, options ArchiveOptions) []byte {
+	tb.Helper()
+	zipper := NewProjectZipper(root)
+	zipper.Archive = options
+
+	result, err := zipper.CreateZip()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer result.Remove()
+
+	data, err := os.ReadFile(result.Path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return data
+}
+
+func TestCreateZipDeterministic(t *testing.T) {
+	root := t.TempDir()
+	generateTree(t, root, 500)
+
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+
+	for _, format := range ArchiveFormats {
+		t.Run(string(format), func(t *testing.T) {
+			options := ArchiveOptions{Format: format}
+
+			runtime.GOMAXPROCS(1)
+			want := readArchive(t, root, options)
+
+			for _, procs := range []int{1, 2, 4, 16} {
+				runtime.GOMAXPROCS(procs)
+				for run := 0; run < 2; run++ {
+					// Modification times must not leak into the archive
+					stamp := time.Now().Add(time.Duration(run+procs) * time.Hour)
+					if err := os.Chtimes(filepath.Join(root, "pkg00", "sub00", "file00000.go"), stamp, stamp); err != nil {
+						t.Fatal(err)
+					}
+
+					if got := readArchive(t, root, options); !bytes.Equal(got, want) {
+						t.Fatalf("GOMAXPROCS=%d run %d: archive differs (%d bytes, want %d)", procs, run, len(got), len(want))
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkScanDirectory(b *testing.B) {
+	root := b.TempDir()
+	generateTree(b, root, 5000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		files, _, err := NewDirectoryScanner(root).ScanDirectory()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(files) == 0 {
+			b.Fatal("no files scanned")
+		}
+	}
+}
+
+func BenchmarkCreateZip(b *testing.B) {
+	root := b.TempDir()
+	generateTree(b, root, 5000)
+
+	for _, format := range ArchiveFormats {
+		b.Run(string(format), func(b *testing.B) {
+			zipper := NewProjectZipper(root)
+			zipper.Archive = ArchiveOptions{Format: format}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				result, err := zipper.CreateZip()
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.SetBytes(result.TotalSize)
+				result.Remove()
+			}
+		})
+	}
+}
+
+// zipManifest builds a manifest from the entries of a zip archive
+func zipManifest(t *testing.T, path string) *Manifest {
+	t.Helper()
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	manifest := &Manifest{}
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if file.Mode()&os.ModeSymlink != 0 {
+			manifest.Files = append(manifest.Files, symlinkEntry(file.Name, string(content)))
+			continue
+		}
+		hash := sha256.Sum256(content)
+		manifest.Files = append(manifest.Files, ManifestEntry{
+			Path:   file.Name,
+			Size:   int64(len(content)),
+			Mode:   uint32(file.Mode().Perm()),
+			SHA256: hex.EncodeToString(hash[:]),
+		})
+	}
+	return manifest
+}
+
+func TestCreateManifestMatchesZip(t *testing.T) {
+	root := t.TempDir()
+	generateTree(t, root, 200)
+	if err := os.Symlink("assets/large.bin", filepath.Join(root, "link.bin")); err != nil {
+		t.Fatal(err)
+	}
+
+	zipper := NewProjectZipper(root)
+	source, err := zipper.CreateManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zipper.CreateZip()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Remove()
+
+	if got := zipManifest(t, archive.Path); !reflect.DeepEqual(source.Manifest, got) {
+		t.Errorf("CreateManifest() does not describe the files in the CreateZip() archive")
+	}
+	if source.FileCount != archive.FileCount || source.TotalSize != archive.TotalSize {
+		t.Errorf("CreateManifest() counted %d files (%d bytes), CreateZip() %d files (%d bytes)",
+			source.FileCount, source.TotalSize, archive.FileCount, archive.TotalSize)
+	}
+
+	before := source.Manifest.Checksum()
+	if err := os.WriteFile(filepath.Join(root, "pkg00", "sub00", "file00000.go"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := zipper.CreateManifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed.Manifest.Checksum() == before {
+		t.Error("Checksum() did not change after a file changed")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/ddod/leanmcp-cli/internal/config"
 	"github.com/ddod/leanmcp-cli/internal/filesystem"
 )
 
@@ -15,8 +16,12 @@ type ProjectCreationFlow struct {
 	Name        string
 	Description string
 	Path        string
+	// ScanOptions holds the file flags; once the path is known the settings
+	// from its project config are merged in ahead of them
+	ScanOptions filesystem.ScanOptions
 	Files       []filesystem.FileInfo
 	Stats       filesystem.FileStats
+	Framework   *filesystem.FrameworkInfo // nil if not recognized
 }
 
 // CollectProjectInfo collects project information interactively
@@ -195,7 +200,18 @@ func (p *ProjectCreationFlow) scanProjectFiles() error {
 	fmt.Println("│ Scanning Directory...                           │")
 	fmt.Println("├─────────────────────────────────────────────────┤")
 	
-	zipper := filesystem.NewProjectZipper(p.Path)
+	options, err := config.ProjectScanOptions(p.Path)
+	if err != nil {
+		return err
+	}
+	options.Include = append(options.Include, p.ScanOptions.Include...)
+	options.Exclude = append(options.Exclude, p.ScanOptions.Exclude...)
+	if p.ScanOptions.Symlinks != "" {
+		options.Symlinks = p.ScanOptions.Symlinks
+	}
+	p.ScanOptions = options
+	
+	zipper := filesystem.NewProjectZipperWithOptions(p.Path, p.ScanOptions)
 	files, stats, err := zipper.PreviewFiles(10) // Preview first 10 files
 	if err != nil {
 		return fmt.Errorf("failed to scan directory: %w", err)
@@ -221,12 +237,21 @@ func (p *ProjectCreationFlow) scanProjectFiles() error {
 				truncateString(file.RelPath, 43))
 		}
 		
-		if len(files) > 5 {
-			fmt.Printf("│   ... and %d more files                        │\n", len(files)-5)
+		if stats.TotalFiles > 5 {
+			fmt.Printf("│   ... and %d more files                        │\n", stats.TotalFiles-5)
+			fmt.Println("│   (run 'leanmcp files list' to see them all)   │")
 		}
 		fmt.Println("│                                                 │")
 	}
 	
+	// Detect the MCP framework; a project it cannot read is still uploaded
+	framework, err := filesystem.DetectFramework(p.Path)
+	if err != nil {
+		fmt.Printf("│ Could not detect framework: %-19s │\n", truncateString(err.Error(), 19))
+		fmt.Println("│                                                 │")
+	}
+	p.Framework = framework
+	
 	return nil
 }
 
@@ -245,6 +270,13 @@ func (p *ProjectCreationFlow) confirmCreation() error {
 	}
 	
 	fmt.Printf("│ Path:        %-35s │\n", truncateString(p.Path, 35))
+	
+	if p.Framework != nil {
+		fmt.Printf("│ Framework:   %-35s │\n", truncateString(p.Framework.String(), 35))
+	} else {
+		fmt.Println("│ Framework:   (not detected)                     │")
+	}
+	
 	fmt.Printf("│ Files:       %d files (%s)                      │\n", 
 		p.Stats.TotalFiles, 
 		filesystem.GetHumanReadableSize(p.Stats.TotalSize))
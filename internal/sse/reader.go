@@ -0,0 +1,202 @@
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event represents a single dispatched Server-Sent Event
+type Event struct {
+	ID   string // the last event ID in effect when the event was dispatched
+	Type string // "message" unless the stream set an event field
+	Data string // data fields joined with newlines
+}
+
+// Reader parses a text/event-stream body following the EventSource grammar
+// from the WHATWG HTML specification. Lines may end in LF, CR or CRLF and
+// have no length limit.
+type Reader struct {
+	r           *bufio.Reader
+	lastEventID string // committed when an event is dispatched
+	idBuffer    string // the most recent id field, not yet dispatched
+	retry       time.Duration
+	started     bool
+	skipLF      bool
+
+	eventType string
+	data      strings.Builder
+	hasData   bool
+}
+
+// NewReader creates a reader that parses events from r
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// LastEventID returns the ID of the last dispatched event, which should be
+// sent as Last-Event-ID when reconnecting
+func (r *Reader) LastEventID() string {
+	return r.lastEventID
+}
+
+// SetLastEventID seeds the last event ID, e.g. when resuming a stream on a new
+// connection that may not repeat the id field
+func (r *Reader) SetLastEventID(id string) {
+	r.lastEventID = id
+	r.idBuffer = id
+}
+
+// Retry returns the reconnection delay requested by the server, or zero if
+// the stream has not sent a retry field
+func (r *Reader) Retry() time.Duration {
+	return r.retry
+}
+
+// Next reads until the next event is dispatched. It returns io.EOF when the
+// stream ends; an event that was not terminated by a blank line is discarded,
+// as the specification requires.
+func (r *Reader) Next() (*Event, error) {
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			if err == io.EOF {
+				r.reset()
+			}
+			return nil, err
+		}
+
+		if len(line) == 0 {
+			if event := r.dispatch(); event != nil {
+				return event, nil
+			}
+			continue
+		}
+
+		r.processLine(line)
+	}
+}
+
+// processLine handles a single non-empty line of the stream
+func (r *Reader) processLine(line []byte) {
+	// Lines starting with a colon are comments, typically keep-alives
+	if line[0] == ':' {
+		return
+	}
+
+	field, value := line, []byte(nil)
+	if i := bytes.IndexByte(line, ':'); i >= 0 {
+		field, value = line[:i], line[i+1:]
+		value = bytes.TrimPrefix(value, []byte(" "))
+	}
+
+	switch string(field) {
+	case "event":
+		r.eventType = string(value)
+	case "data":
+		r.data.Write(value)
+		r.data.WriteByte('\n')
+		r.hasData = true
+	case "id":
+		if bytes.IndexByte(value, 0) < 0 {
+			r.idBuffer = string(value)
+		}
+	case "retry":
+		if ms, err := strconv.ParseUint(string(value), 10, 63); err == nil && isDigits(value) {
+			r.retry = time.Duration(ms) * time.Millisecond
+		}
+	}
+	// Unknown fields are ignored
+}
+
+// dispatch builds the pending event, or returns nil if no data was buffered
+func (r *Reader) dispatch() *Event {
+	r.lastEventID = r.idBuffer
+
+	if !r.hasData {
+		r.reset()
+		return nil
+	}
+
+	event := &Event{
+		ID:   r.lastEventID,
+		Type: r.eventType,
+		Data: strings.TrimSuffix(r.data.String(), "\n"),
+	}
+	if event.Type == "" {
+		event.Type = "message"
+	}
+
+	r.reset()
+	return event
+}
+
+// reset clears the event type and data buffers
+func (r *Reader) reset() {
+	r.eventType = ""
+	r.data.Reset()
+	r.hasData = false
+}
+
+// readLine returns the next line without its terminator. A CR terminator is
+// remembered rather than followed by a blocking peek so that a lone CR
+// dispatches immediately on a live stream.
+func (r *Reader) readLine() ([]byte, error) {
+	var line []byte
+
+	for {
+		b, err := r.r.ReadByte()
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				// A final line without a terminator is incomplete; drop it
+				return nil, io.EOF
+			}
+			return nil, err
+		}
+
+		if r.skipLF {
+			r.skipLF = false
+			if b == '\n' {
+				continue
+			}
+		}
+
+		if !r.started {
+			r.started = true
+			// Strip a leading UTF-8 byte order mark
+			if b == 0xEF {
+				bom, _ := r.r.Peek(2)
+				if len(bom) == 2 && bom[0] == 0xBB && bom[1] == 0xBF {
+					r.r.Discard(2)
+					continue
+				}
+			}
+		}
+
+		switch b {
+		case '\n':
+			return line, nil
+		case '\r':
+			r.skipLF = true
+			return line, nil
+		default:
+			line = append(line, b)
+		}
+	}
+}
+
+// isDigits reports whether value is a non-empty run of ASCII digits
+func isDigits(value []byte) bool {
+	if len(value) == 0 {
+		return false
+	}
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
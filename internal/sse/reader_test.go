@@ -0,0 +1,186 @@
+package sse
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readAll returns every event dispatched from stream
+func readAll(t *testing.T, stream string) ([]Event, *Reader) {
+	t.Helper()
+	reader := NewReader(strings.NewReader(stream))
+	var events []Event
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return events, reader
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		events = append(events, *event)
+	}
+}
+
+func TestReaderEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   []Event
+	}{
+		{
+			name:   "LF line endings",
+			stream: "data: one\n\ndata: two\n\n",
+			want:   []Event{{Type: "message", Data: "one"}, {Type: "message", Data: "two"}},
+		},
+		{
+			name:   "CRLF line endings",
+			stream: "data: one\r\n\r\ndata: two\r\n\r\n",
+			want:   []Event{{Type: "message", Data: "one"}, {Type: "message", Data: "two"}},
+		},
+		{
+			name:   "CR line endings",
+			stream: "data: one\r\rdata: two\r\r",
+			want:   []Event{{Type: "message", Data: "one"}, {Type: "message", Data: "two"}},
+		},
+		{
+			name:   "mixed line endings",
+			stream: "data: a\rdata: b\r\ndata: c\n\r\n",
+			want:   []Event{{Type: "message", Data: "a\nb\nc"}},
+		},
+		{
+			name:   "leading BOM",
+			stream: "\xEF\xBB\xBFdata: one\n\n",
+			want:   []Event{{Type: "message", Data: "one"}},
+		},
+		{
+			name:   "BOM only stripped at the start",
+			stream: "data: one\n\n\xEF\xBB\xBFdata: two\n\n",
+			want:   []Event{{Type: "message", Data: "one"}},
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: first\ndata: second\ndata\ndata:third\n\n",
+			want:   []Event{{Type: "message", Data: "first\nsecond\n\nthird"}},
+		},
+		{
+			name:   "only one leading space is removed",
+			stream: "data:  indented\n\n",
+			want:   []Event{{Type: "message", Data: " indented"}},
+		},
+		{
+			name:   "colon in value",
+			stream: "data: a: b\n\n",
+			want:   []Event{{Type: "message", Data: "a: b"}},
+		},
+		{
+			name:   "event type",
+			stream: "event: progress\ndata: {}\n\ndata: plain\n\n",
+			want:   []Event{{Type: "progress", Data: "{}"}, {Type: "message", Data: "plain"}},
+		},
+		{
+			name:   "comments are ignored",
+			stream: ": keep-alive\ndata: one\n:\n\n: another\n\n",
+			want:   []Event{{Type: "message", Data: "one"}},
+		},
+		{
+			name:   "unknown fields are ignored",
+			stream: "foo: bar\ndata: one\nDATA: two\n\n",
+			want:   []Event{{Type: "message", Data: "one"}},
+		},
+		{
+			name:   "event without data is not dispatched",
+			stream: "event: ping\n\ndata: one\n\n",
+			want:   []Event{{Type: "message", Data: "one"}},
+		},
+		{
+			name:   "id carries over to later events",
+			stream: "id: 1\ndata: one\n\ndata: two\n\nid\ndata: three\n\n",
+			want: []Event{
+				{ID: "1", Type: "message", Data: "one"},
+				{ID: "1", Type: "message", Data: "two"},
+				{ID: "", Type: "message", Data: "three"},
+			},
+		},
+		{
+			name:   "id containing NUL is ignored",
+			stream: "id: 1\ndata: one\n\nid: 2\x003\ndata: two\n\n",
+			want: []Event{
+				{ID: "1", Type: "message", Data: "one"},
+				{ID: "1", Type: "message", Data: "two"},
+			},
+		},
+		{
+			name:   "unterminated event is discarded",
+			stream: "data: one\n\ndata: partial\n",
+			want:   []Event{{Type: "message", Data: "one"}},
+		},
+		{
+			name:   "unterminated line is discarded",
+			stream: "data: one\n\ndata: partial",
+			want:   []Event{{Type: "message", Data: "one"}},
+		},
+		{
+			name:   "long line",
+			stream: "data: " + strings.Repeat("x", 200*1024) + "\n\n",
+			want:   []Event{{Type: "message", Data: strings.Repeat("x", 200*1024)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := readAll(t, tt.stream)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReaderRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   time.Duration
+	}{
+		{"milliseconds", "retry: 1500\n\n", 1500 * time.Millisecond},
+		{"later value wins", "retry: 100\nretry: 200\n\n", 200 * time.Millisecond},
+		{"not a number", "retry: soon\n\n", 0},
+		{"negative", "retry: -5\n\n", 0},
+		{"trailing garbage", "retry: 10s\n\n", 0},
+		{"empty", "retry:\n\n", 0},
+		{"invalid keeps earlier value", "retry: 100\nretry: x\n\n", 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, reader := readAll(t, tt.stream)
+			if got := reader.Retry(); got != tt.want {
+				t.Errorf("Retry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReaderLastEventID(t *testing.T) {
+	// The id of an event without data still becomes the last event ID
+	_, reader := readAll(t, "id: 1\ndata: one\n\nid: 2\n\n")
+	if got := reader.LastEventID(); got != "2" {
+		t.Errorf("LastEventID() = %q, want %q", got, "2")
+	}
+
+	// A seeded ID is used until the stream sends its own
+	reader = NewReader(strings.NewReader("data: one\n\n"))
+	reader.SetLastEventID("41")
+	event, err := reader.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.ID != "41" {
+		t.Errorf("event ID = %q, want the seeded %q", event.ID, "41")
+	}
+}
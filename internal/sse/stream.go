@@ -0,0 +1,148 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ErrStop can be returned by an event handler to end the stream cleanly
+var ErrStop = errors.New("sse: stop")
+
+// DefaultRetry is the reconnection delay used until the server sends a retry field
+const DefaultRetry = 3 * time.Second
+
+// ConnectFunc opens a connection to the event stream. lastEventID is empty on
+// the first attempt and should be sent as the Last-Event-ID header otherwise.
+type ConnectFunc func(ctx context.Context, lastEventID string) (*http.Response, error)
+
+// Stream consumes an event stream, reconnecting and resuming from the last
+// event ID whenever the connection drops
+type Stream struct {
+	Connect ConnectFunc
+
+	// MaxRetries is the number of consecutive failed reconnects tolerated
+	// before giving up. A successfully received event resets the count.
+	MaxRetries int
+
+	// Retry is the reconnection delay used until the server sends a retry
+	// field; DefaultRetry if zero
+	Retry time.Duration
+
+	// OnReconnect, if set, is called before each reconnection attempt
+	OnReconnect func(attempt int, err error)
+}
+
+// Run reads events and passes them to handler until the handler returns
+// ErrStop (Run then returns nil), the handler fails, the server answers
+// 204 No Content, or reconnecting is no longer possible
+func (s *Stream) Run(ctx context.Context, handler func(*Event) error) error {
+	state := &streamState{retry: s.Retry}
+	if state.retry <= 0 {
+		state.retry = DefaultRetry
+	}
+	attempts := 0
+
+	for {
+		err := s.consume(ctx, state, func(event *Event) error {
+			attempts = 0
+			return handler(event)
+		})
+
+		switch {
+		case errors.Is(err, ErrStop), errors.Is(err, errNoContent):
+			return nil
+		case ctx.Err() != nil:
+			return ctx.Err()
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+
+		attempts++
+		if attempts > s.MaxRetries {
+			return fmt.Errorf("event stream lost after %d reconnect attempt(s): %w", s.MaxRetries, err)
+		}
+		if s.OnReconnect != nil {
+			s.OnReconnect(attempts, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(state.retry):
+		}
+	}
+}
+
+// streamState is carried across reconnections
+type streamState struct {
+	lastEventID string
+	retry       time.Duration
+}
+
+// consume runs a single connection until it ends. Errors that reconnecting
+// cannot fix, including handler failures, are wrapped in permanentError.
+func (s *Stream) consume(ctx context.Context, state *streamState, handler func(*Event) error) error {
+	resp, err := s.Connect(ctx, state.lastEventID)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNoContent:
+		// The server asked us to stop reconnecting
+		return errNoContent
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("stream unavailable (status %d): %s", resp.StatusCode, string(body))
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		body, _ := io.ReadAll(resp.Body)
+		return &permanentError{fmt.Errorf("stream request failed (status %d): %s", resp.StatusCode, string(body))}
+	}
+
+	reader := NewReader(resp.Body)
+	reader.SetLastEventID(state.lastEventID)
+
+	for {
+		event, err := reader.Next()
+		state.lastEventID = reader.LastEventID()
+		if reader.Retry() > 0 {
+			state.retry = reader.Retry()
+		}
+		if err != nil {
+			if err == io.EOF {
+				return errors.New("stream closed by server")
+			}
+			return err
+		}
+
+		if err := handler(event); err != nil {
+			if errors.Is(err, ErrStop) {
+				return ErrStop
+			}
+			return &permanentError{err}
+		}
+	}
+}
+
+// errNoContent signals a 204 response, which ends the stream without error
+var errNoContent = errors.New("sse: no content")
+
+// Permanent marks an error returned from a ConnectFunc as not worth retrying
+func Permanent(err error) error {
+	return &permanentError{err}
+}
+
+// permanentError wraps failures that reconnecting cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
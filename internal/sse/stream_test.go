@@ -0,0 +1,142 @@
+package sse
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// response builds an event stream response with the given status and body
+func response(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestStreamResumesFromLastEventID(t *testing.T) {
+	bodies := []string{
+		"retry: 1\nid: 1\ndata: one\n\nid: 2\ndata: two\n\ndata: cut off",
+		"id: 3\ndata: three\n\n",
+	}
+	var lastIDs []string
+	stream := &Stream{
+		MaxRetries: 1,
+		Connect: func(ctx context.Context, lastEventID string) (*http.Response, error) {
+			lastIDs = append(lastIDs, lastEventID)
+			if len(lastIDs) > len(bodies) {
+				return response(http.StatusNoContent, ""), nil
+			}
+			return response(http.StatusOK, bodies[len(lastIDs)-1]), nil
+		},
+	}
+
+	var got []string
+	err := stream.Run(context.Background(), func(event *Event) error {
+		got = append(got, event.Data)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if want := "one two three"; strings.Join(got, " ") != want {
+		t.Errorf("events = %v, want %s", got, want)
+	}
+	if want := ",2,3"; strings.Join(lastIDs, ",") != want {
+		t.Errorf("Last-Event-ID sent = %q, want %q", strings.Join(lastIDs, ","), want)
+	}
+}
+
+func TestStreamStops(t *testing.T) {
+	errHandler := errors.New("handler failed")
+	errConnect := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		connErr  error
+		handler  func(*Event) error
+		wantErr  error // matched with errors.Is; nil means success
+		wantConn int
+	}{
+		{
+			name:     "handler stops",
+			status:   http.StatusOK,
+			body:     "data: one\n\ndata: two\n\n",
+			handler:  func(*Event) error { return ErrStop },
+			wantConn: 1,
+		},
+		{
+			name:     "no content",
+			status:   http.StatusNoContent,
+			wantConn: 1,
+		},
+		{
+			name:     "handler error is permanent",
+			status:   http.StatusOK,
+			body:     "data: one\n\n",
+			handler:  func(*Event) error { return errHandler },
+			wantErr:  errHandler,
+			wantConn: 1,
+		},
+		{
+			name:     "client error is permanent",
+			status:   http.StatusUnauthorized,
+			wantErr:  errAny,
+			wantConn: 1,
+		},
+		{
+			name:     "permanent connect error",
+			connErr:  Permanent(errConnect),
+			wantErr:  errAny,
+			wantConn: 1,
+		},
+		{
+			name:     "retries are limited",
+			connErr:  errConnect,
+			wantErr:  errConnect,
+			wantConn: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connects := 0
+			stream := &Stream{
+				MaxRetries: 2,
+				Retry:      time.Millisecond,
+				Connect: func(ctx context.Context, lastEventID string) (*http.Response, error) {
+					connects++
+					if tt.connErr != nil {
+						return nil, tt.connErr
+					}
+					return response(tt.status, tt.body), nil
+				},
+			}
+			handler := tt.handler
+			if handler == nil {
+				handler = func(*Event) error { return nil }
+			}
+
+			err := stream.Run(context.Background(), handler)
+
+			switch {
+			case tt.wantErr == nil && err != nil:
+				t.Errorf("Run() error = %v, want nil", err)
+			case tt.wantErr == errAny && err == nil:
+				t.Error("Run() succeeded, want an error")
+			case tt.wantErr != nil && tt.wantErr != errAny && !errors.Is(err, tt.wantErr):
+				t.Errorf("Run() error = %v, want %v", err, tt.wantErr)
+			}
+			if connects != tt.wantConn {
+				t.Errorf("connected %d times, want %d", connects, tt.wantConn)
+			}
+		})
+	}
+}
+
+// errAny matches any non-nil error in TestStreamStops
+var errAny = errors.New("any error")
@@ -0,0 +1,266 @@
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ddod/leanmcp-cli/internal/api"
+	"github.com/ddod/leanmcp-cli/internal/filesystem"
+)
+
+// IncrementalResult summarizes an incremental upload
+type IncrementalResult struct {
+	S3Location    string
+	TotalFiles    int
+	ChangedFiles  int   // files whose contents the server did not have
+	UploadedBytes int64 // size of the distinct blobs that were sent
+}
+
+// UploadIncremental sends only the files whose contents the server does not
+// already have, then asks it to assemble the project from the manifest.
+// It returns api.ErrIncrementalUnsupported if the server cannot do this, in
+// which case the archive should be uploaded with Upload instead.
+func (u *Uploader) UploadIncremental(ctx context.Context, root string, manifest *filesystem.Manifest) (*IncrementalResult, error) {
+	blobs := manifest.UniqueBlobs()
+	hashes := make([]string, 0, len(blobs))
+	for _, blob := range blobs {
+		hashes = append(hashes, blob.SHA256)
+	}
+
+	missing, err := u.Client.GetMissingBlobs(ctx, u.ProjectID, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	byHash := make(map[string]filesystem.ManifestEntry, len(blobs))
+	for _, blob := range blobs {
+		byHash[blob.SHA256] = blob
+	}
+
+	result := &IncrementalResult{TotalFiles: len(manifest.Files)}
+	missingHashes := make(map[string]bool, len(missing))
+	for _, blob := range missing {
+		entry, ok := byHash[blob.Hash]
+		if !ok {
+			return nil, fmt.Errorf("server requested unknown blob %s", blob.Hash)
+		}
+		missingHashes[blob.Hash] = true
+		result.UploadedBytes += entry.Size
+	}
+	for _, file := range manifest.Files {
+		if missingHashes[file.SHA256] {
+			result.ChangedFiles++
+		}
+	}
+
+	u.progress = newProgress(result.UploadedBytes, u.OnProgress)
+	if err := u.uploadBlobs(ctx, root, missing, byHash); err != nil {
+		return nil, err
+	}
+
+	files := make([]api.ManifestFile, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		files = append(files, api.ManifestFile{
+			Path:    file.Path,
+			Size:    file.Size,
+			Mode:    file.Mode,
+			SHA256:  file.SHA256,
+			Symlink: file.Symlink,
+		})
+	}
+
+	commitResp, err := u.Client.CommitManifest(ctx, u.ProjectID, files)
+	if err != nil {
+		return nil, err
+	}
+	result.S3Location = commitResp.S3Location
+
+	return result, nil
+}
+
+// uploadBlobs sends the missing blobs using a pool of workers
+func (u *Uploader) uploadBlobs(ctx context.Context, root string, missing []api.BlobUploadURL, byHash map[string]filesystem.ManifestEntry) error {
+	if len(missing) == 0 {
+		return nil
+	}
+	u.progress.add(0)
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	stop := make(chan struct{})
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			close(stop)
+		}
+	}
+
+	jobs := make(chan api.BlobUploadURL)
+	for i := 0; i < u.concurrency(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for blob := range jobs {
+				if err := u.uploadBlob(ctx, root, byHash[blob.Hash], blob.URL); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, blob := range missing {
+		select {
+		case jobs <- blob:
+		case <-stop:
+			break feed
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// uploadBlob sends one file, retrying transient failures with backoff and
+// fetching a fresh URL if the pre-signed one has expired
+func (u *Uploader) uploadBlob(ctx context.Context, root string, entry filesystem.ManifestEntry, url string) error {
+	var lastErr error
+	for attempt := 0; attempt <= u.MaxRetries; attempt++ {
+		if err := backoff(ctx, attempt); err != nil {
+			return err
+		}
+
+		if url == "" {
+			missing, err := u.Client.GetMissingBlobs(ctx, u.ProjectID, []string{entry.SHA256})
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if len(missing) == 0 {
+				// Uploaded in the meantime, e.g. by a concurrent deploy
+				return nil
+			}
+			url = missing[0].URL
+		}
+
+		err := u.putBlob(ctx, root, entry, url)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		lastErr = err
+
+		retry, expired := classify(err)
+		if !retry {
+			return fmt.Errorf("%s: %w", entry.Path, err)
+		}
+		if expired {
+			url = ""
+		}
+	}
+
+	return fmt.Errorf("%s failed after %d attempts: %w", entry.Path, u.MaxRetries+1, lastErr)
+}
+
+// putBlob makes a single upload attempt, rolling back progress on failure.
+// A symlink's blob is its target rather than the file it points to. The
+// contents are hashed again as they are sent, and the upload is aborted if
+// they no longer match the manifest.
+func (u *Uploader) putBlob(ctx context.Context, root string, entry filesystem.ManifestEntry, url string) error {
+	var content io.Reader = strings.NewReader(entry.Symlink)
+	if entry.Symlink == "" {
+		file, err := os.Open(filepath.Join(root, filepath.FromSlash(entry.Path)))
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", entry.Path, err)
+		}
+		defer file.Close()
+		content = file
+	}
+
+	verifier := newVerifyingReader(content, entry)
+	body, sent := u.progress.reader(verifier)
+	err := u.Client.UploadBlob(ctx, url, body, entry.Size)
+	if verifier.err != nil {
+		// Report the mismatch rather than how the transport failed
+		err = verifier.err
+	}
+	if err != nil {
+		u.progress.add(-*sent)
+	}
+	return err
+}
+
+// errContentChanged is returned when a file no longer matches its manifest
+// entry, i.e. it was modified after the project was scanned
+var errContentChanged = errors.New("file changed since the project was scanned; run the command again")
+
+// verifyingReader hashes a blob as it is read and fails instead of delivering
+// the final bytes if the size or hash differ from the manifest entry, so a
+// changed file is never stored under the old hash
+type verifyingReader struct {
+	r    io.Reader
+	want filesystem.ManifestEntry
+	hash hash.Hash
+	n    int64
+	err  error
+}
+
+func newVerifyingReader(r io.Reader, want filesystem.ManifestEntry) *verifyingReader {
+	return &verifyingReader{r: r, want: want, hash: sha256.New()}
+}
+
+func (vr *verifyingReader) Read(p []byte) (int, error) {
+	if vr.err != nil {
+		return 0, vr.err
+	}
+
+	// Never read past the expected size, so extra bytes are not sent
+	if remaining := vr.want.Size - vr.n; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := vr.r.Read(p)
+	vr.hash.Write(p[:n])
+	vr.n += int64(n)
+
+	switch {
+	case err != nil && err != io.EOF:
+		return n, err
+	case vr.n < vr.want.Size:
+		if err == io.EOF {
+			vr.err = errContentChanged
+			return 0, vr.err
+		}
+		return n, nil
+	}
+
+	// All expected bytes are read: the file must end here and match the hash
+	var extra [1]byte
+	if m, _ := io.ReadFull(vr.r, extra[:]); m > 0 || hex.EncodeToString(vr.hash.Sum(nil)) != vr.want.SHA256 {
+		vr.err = errContentChanged
+		return 0, vr.err
+	}
+	return n, io.EOF
+}
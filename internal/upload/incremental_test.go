@@ -0,0 +1,83 @@
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ddod/leanmcp-cli/internal/api"
+	"github.com/ddod/leanmcp-cli/internal/filesystem"
+)
+
+func TestPutBlobVerifiesContents(t *testing.T) {
+	const scanned = "contents at scan time\n"
+	sum := sha256.Sum256([]byte(scanned))
+	entry := filesystem.ManifestEntry{
+		Path:   "main.go",
+		Size:   int64(len(scanned)),
+		Mode:   0644,
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"unchanged", scanned, false},
+		{"same size", "CONTENTS AT SCAN TIME\n", true},
+		{"longer", scanned + "more\n", true},
+		{"shorter", "contents\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var stored []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					// The client aborted the request
+					return
+				}
+				mu.Lock()
+				stored = append(stored, string(body))
+				mu.Unlock()
+			}))
+
+			root := t.TempDir()
+			if err := os.WriteFile(filepath.Join(root, entry.Path), []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			uploader := NewUploader(api.NewClient("airtrain_test", server.URL), "proj", root)
+			err := uploader.uploadBlob(context.Background(), root, entry, server.URL)
+			server.Close() // waits for the handler
+			if tt.wantErr != errors.Is(err, errContentChanged) {
+				t.Fatalf("uploadBlob() error = %v, want content changed: %v", err, tt.wantErr)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			want := 1
+			if tt.wantErr {
+				// Not retried, and the server never received a complete body
+				want = 0
+			}
+			if len(stored) != want {
+				t.Fatalf("server stored %d blobs, want %d", len(stored), want)
+			}
+			if want == 1 && stored[0] != scanned {
+				t.Errorf("server stored %q, want %q", stored[0], scanned)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package upload
+
+import (
+	"io"
+	"sync"
+)
+
+// ProgressFunc receives the number of bytes uploaded so far and the total
+type ProgressFunc func(sent, total int64)
+
+// progress tracks bytes sent across concurrent part uploads
+type progress struct {
+	mu       sync.Mutex
+	sent     int64
+	total    int64
+	callback ProgressFunc
+}
+
+func newProgress(total int64, callback ProgressFunc) *progress {
+	return &progress{total: total, callback: callback}
+}
+
+// add records n more bytes sent; a negative n rolls back a failed attempt
+func (p *progress) add(n int64) {
+	if p == nil || p.callback == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sent += n
+	p.callback(p.sent, p.total)
+}
+
+// reader wraps r so every read is reported. The returned counter holds the
+// bytes read through it, so a retry can roll them back.
+func (p *progress) reader(r io.Reader) (io.Reader, *int64) {
+	var count int64
+	return &progressReader{r: r, progress: p, count: &count}, &count
+}
+
+// progressReader reports bytes as the HTTP client reads the request body
+type progressReader struct {
+	r        io.Reader
+	progress *progress
+	count    *int64
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	if n > 0 {
+		*pr.count += int64(n)
+		pr.progress.add(int64(n))
+	}
+	return n, err
+}
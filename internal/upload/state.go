@@ -0,0 +1,115 @@
+package upload
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ddod/leanmcp-cli/internal/api"
+)
+
+// StateFileName is the name of the upload state file inside .leanmcp
+const StateFileName = "upload-state.json"
+
+// State records an unfinished multipart upload so it can be resumed
+type State struct {
+	ProjectID  string              `json:"projectId"`
+	UploadID   string              `json:"uploadId"`
+	S3Location string              `json:"s3Location"`
+	Checksum   string              `json:"checksum"` // SHA-256 of the archive
+	Size       int64               `json:"size"`
+	PartSize   int64               `json:"partSize"`
+	Parts      []api.CompletedPart `json:"parts"`
+	StartedAt  time.Time           `json:"startedAt"`
+}
+
+// StatePath returns the location of the upload state file for a project
+func StatePath(projectPath string) string {
+	return filepath.Join(projectPath, ".leanmcp", StateFileName)
+}
+
+// LoadState reads an upload state file. A missing file yields nil, nil.
+func LoadState(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read upload state: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid upload state: %w", err)
+	}
+
+	return &state, nil
+}
+
+// Save writes the state file atomically so an interruption never leaves it
+// half-written
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal upload state: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write upload state: %w", err)
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// RemoveState deletes the state file once an upload is finished or abandoned
+func RemoveState(path string) error {
+	err := os.Remove(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// PartCount returns the number of parts the archive is split into
+func (s *State) PartCount() int {
+	if s.PartSize <= 0 {
+		return 0
+	}
+	return int((s.Size + s.PartSize - 1) / s.PartSize)
+}
+
+// partRange returns the byte offset and length of a part
+func (s *State) partRange(partNumber int) (int64, int64) {
+	offset := int64(partNumber-1) * s.PartSize
+	length := s.PartSize
+	if offset+length > s.Size {
+		length = s.Size - offset
+	}
+	return offset, length
+}
+
+// completed returns the uploaded parts keyed by part number
+func (s *State) completed() map[int]bool {
+	done := make(map[int]bool, len(s.Parts))
+	for _, part := range s.Parts {
+		done[part.PartNumber] = true
+	}
+	return done
+}
+
+// sortedParts returns the uploaded parts in part number order
+func (s *State) sortedParts() []api.CompletedPart {
+	parts := append([]api.CompletedPart(nil), s.Parts...)
+	sort.Slice(parts, func(i, j int) bool {
+		return parts[i].PartNumber < parts[j].PartNumber
+	})
+	return parts
+}
@@ -0,0 +1,383 @@
+// Package upload sends project archives to S3, splitting large archives into
+// parts that are uploaded in parallel and can be resumed after an interruption.
+package upload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ddod/leanmcp-cli/internal/api"
+	"github.com/ddod/leanmcp-cli/internal/filesystem"
+)
+
+const (
+	// DefaultPartSize is the size of each part; archives no larger than one
+	// part are sent with a single PUT
+	DefaultPartSize = 8 * 1024 * 1024
+	// DefaultConcurrency is the number of parts uploaded at once
+	DefaultConcurrency = 4
+	// DefaultMaxRetries is how often a failed part is retried
+	DefaultMaxRetries = 3
+)
+
+// Uploader uploads a project archive and returns its S3 location
+type Uploader struct {
+	Client      *api.Client
+	ProjectID   string
+	StatePath   string // where unfinished uploads are recorded
+	PartSize    int64
+	Concurrency int
+	MaxRetries  int
+
+	// OnResume is called when an interrupted upload of the same archive is
+	// picked up again, with the number of parts already uploaded
+	OnResume func(uploaded, total int)
+
+	// OnProgress is called as bytes are sent, including bytes of parts
+	// uploaded before a resume. Calls are serialized.
+	OnProgress ProgressFunc
+
+	progress *progress
+}
+
+// NewUploader creates an uploader that keeps its state in the project's
+// .leanmcp directory
+func NewUploader(client *api.Client, projectID, projectPath string) *Uploader {
+	return &Uploader{
+		Client:      client,
+		ProjectID:   projectID,
+		StatePath:   StatePath(projectPath),
+		PartSize:    DefaultPartSize,
+		Concurrency: DefaultConcurrency,
+		MaxRetries:  DefaultMaxRetries,
+	}
+}
+
+// Upload sends the archive. If an earlier upload of the same archive was
+// interrupted, only the missing parts are sent. On failure the state file is
+// kept so the next call can resume.
+func (u *Uploader) Upload(ctx context.Context, archive *filesystem.ZipResult) (string, error) {
+	archivePath, size, checksum := archive.Path, archive.Size, archive.Checksum
+	if checksum == "" {
+		var err error
+		checksum, err = fileChecksum(archivePath)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	state, err := LoadState(u.StatePath)
+	if err != nil {
+		return "", err
+	}
+
+	u.progress = newProgress(size, u.OnProgress)
+
+	if state != nil && (state.ProjectID != u.ProjectID || state.Checksum != checksum || state.Size != size) {
+		// The archive changed since the interrupted upload; start over
+		_ = u.Client.AbortMultipartUpload(ctx, state.ProjectID, state.UploadID)
+		if err := RemoveState(u.StatePath); err != nil {
+			return "", fmt.Errorf("failed to remove upload state: %w", err)
+		}
+		state = nil
+	}
+
+	if state == nil {
+		if size <= u.partSize() {
+			return u.uploadSingle(ctx, archive)
+		}
+
+		upload, err := u.Client.CreateMultipartUpload(ctx, u.ProjectID, api.MultipartUploadRequest{
+			FileName: archive.Format.FileName(),
+			FileType: archive.Format.ContentType(),
+			FileSize: size,
+			PartSize: u.partSize(),
+		})
+		if errors.Is(err, api.ErrMultipartUnsupported) {
+			return u.uploadSingle(ctx, archive)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		state = &State{
+			ProjectID:  u.ProjectID,
+			UploadID:   upload.UploadID,
+			S3Location: upload.S3Location,
+			Checksum:   checksum,
+			Size:       size,
+			PartSize:   upload.PartSize,
+			StartedAt:  time.Now(),
+		}
+		if state.PartSize <= 0 {
+			state.PartSize = u.partSize()
+		}
+		if err := state.Save(u.StatePath); err != nil {
+			return "", err
+		}
+	} else if u.OnResume != nil {
+		u.OnResume(len(state.Parts), state.PartCount())
+	}
+
+	if err := u.uploadParts(ctx, archivePath, state); err != nil {
+		return "", err
+	}
+
+	completeResp, err := u.Client.CompleteMultipartUpload(ctx, u.ProjectID, state.UploadID, state.sortedParts())
+	if err != nil {
+		return "", err
+	}
+
+	if err := RemoveState(u.StatePath); err != nil {
+		return "", fmt.Errorf("failed to remove upload state: %w", err)
+	}
+
+	if completeResp.S3Location != "" {
+		return completeResp.S3Location, nil
+	}
+	return state.S3Location, nil
+}
+
+// uploadSingle sends the whole archive with one PUT
+func (u *Uploader) uploadSingle(ctx context.Context, archive *filesystem.ZipResult) (string, error) {
+	uploadResp, err := u.Client.GetUploadURL(ctx, u.ProjectID, archive.Format.FileName(), archive.Format.ContentType(), archive.Size)
+	if err != nil {
+		return "", fmt.Errorf("failed to get upload URL: %w", err)
+	}
+
+	file, err := archive.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	u.progress.add(0)
+	body, _ := u.progress.reader(file)
+	if err := u.Client.UploadToS3(ctx, uploadResp.URL, archive.Format.ContentType(), body, archive.Size); err != nil {
+		return "", err
+	}
+
+	return uploadResp.S3Location, nil
+}
+
+// uploadParts sends every part not yet recorded in state using a pool of
+// workers, saving the state after each part
+func (u *Uploader) uploadParts(ctx context.Context, archivePath string, state *State) error {
+	done := state.completed()
+	var pending []int
+	var uploaded int64
+	for partNumber := 1; partNumber <= state.PartCount(); partNumber++ {
+		if done[partNumber] {
+			_, length := state.partRange(partNumber)
+			uploaded += length
+		} else {
+			pending = append(pending, partNumber)
+		}
+	}
+	u.progress.add(uploaded)
+	if len(pending) == 0 {
+		return nil
+	}
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	urls, err := u.partURLs(ctx, state, pending)
+	if err != nil {
+		return err
+	}
+
+	// A failed part stops new parts from starting, but parts already in
+	// flight are allowed to finish so they are recorded for the resume
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	stop := make(chan struct{})
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			close(stop)
+		}
+	}
+
+	jobs := make(chan int)
+	for i := 0; i < u.concurrency(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for partNumber := range jobs {
+				etag, err := u.uploadPart(ctx, file, state, partNumber, urls[partNumber])
+				if err != nil {
+					fail(err)
+					continue
+				}
+
+				mu.Lock()
+				state.Parts = append(state.Parts, api.CompletedPart{PartNumber: partNumber, ETag: etag})
+				err = state.Save(u.StatePath)
+				mu.Unlock()
+				if err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, partNumber := range pending {
+		select {
+		case jobs <- partNumber:
+		case <-stop:
+			break feed
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// uploadPart sends one part, retrying transient failures with backoff and
+// fetching a fresh URL if the pre-signed one has expired
+func (u *Uploader) uploadPart(ctx context.Context, file *os.File, state *State, partNumber int, url string) (string, error) {
+	offset, length := state.partRange(partNumber)
+
+	var lastErr error
+	for attempt := 0; attempt <= u.MaxRetries; attempt++ {
+		if err := backoff(ctx, attempt); err != nil {
+			return "", err
+		}
+
+		if url == "" {
+			urls, err := u.partURLs(ctx, state, []int{partNumber})
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			url = urls[partNumber]
+		}
+
+		body, sent := u.progress.reader(io.NewSectionReader(file, offset, length))
+		etag, err := u.Client.UploadPart(ctx, url, body, length)
+		if err == nil {
+			return etag, nil
+		}
+		u.progress.add(-*sent)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		lastErr = err
+
+		retry, expired := classify(err)
+		if !retry {
+			return "", fmt.Errorf("part %d: %w", partNumber, err)
+		}
+		if expired {
+			url = ""
+		}
+	}
+
+	return "", fmt.Errorf("part %d failed after %d attempts: %w", partNumber, u.MaxRetries+1, lastErr)
+}
+
+// backoff waits before a retry: nothing before the first attempt, then 1s,
+// 2s, 4s and so on
+func backoff(ctx context.Context, attempt int) error {
+	if attempt == 0 {
+		return nil
+	}
+	select {
+	case <-time.After(time.Duration(1<<(attempt-1)) * time.Second):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// classify reports whether a failed upload should be retried and whether the
+// pre-signed URL expired and must be fetched again. Network errors, 429 and
+// 5xx responses are transient; other rejections and files that changed since
+// the scan are permanent.
+func classify(err error) (retry, expired bool) {
+	if errors.Is(err, errContentChanged) {
+		return false, false
+	}
+
+	var uploadErr *api.UploadError
+	if !errors.As(err, &uploadErr) {
+		return true, false
+	}
+
+	switch {
+	case uploadErr.StatusCode == http.StatusForbidden:
+		return true, true
+	case uploadErr.StatusCode == http.StatusTooManyRequests || uploadErr.StatusCode >= 500:
+		return true, false
+	default:
+		return false, false
+	}
+}
+
+// partURLs fetches pre-signed URLs keyed by part number
+func (u *Uploader) partURLs(ctx context.Context, state *State, partNumbers []int) (map[int]string, error) {
+	parts, err := u.Client.GetPartUploadURLs(ctx, state.ProjectID, state.UploadID, partNumbers)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make(map[int]string, len(parts))
+	for _, part := range parts {
+		urls[part.PartNumber] = part.URL
+	}
+	return urls, nil
+}
+
+func (u *Uploader) partSize() int64 {
+	if u.PartSize <= 0 {
+		return DefaultPartSize
+	}
+	return u.PartSize
+}
+
+func (u *Uploader) concurrency() int {
+	if u.Concurrency <= 0 {
+		return DefaultConcurrency
+	}
+	return u.Concurrency
+}
+
+// fileChecksum returns the hex SHA-256 of a file
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash archive: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,58 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ddod/leanmcp-cli/internal/api"
+	"github.com/ddod/leanmcp-cli/internal/filesystem"
+)
+
+func TestUploadSingleCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/projects/proj/upload-url":
+			fmt.Fprintf(w, `{"url": %q, "s3Location": "s3://bucket/proj.zip"}`, server.URL+"/s3")
+		case "/s3":
+			// Hold the PUT until the client gives up
+			io.Copy(io.Discard, r.Body)
+			cancel()
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	root := t.TempDir()
+	path := filepath.Join(root, "archive.zip")
+	if err := os.WriteFile(path, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	archive := &filesystem.ZipResult{Path: path, Format: filesystem.FormatZip, Size: 7}
+
+	uploader := NewUploader(api.NewClient("airtrain_test", server.URL), "proj", root)
+	start := time.Now()
+	_, err := uploader.Upload(ctx, archive)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Upload() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Upload() returned after %v, want it to stop when cancelled", elapsed)
+	}
+}